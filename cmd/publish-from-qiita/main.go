@@ -2,95 +2,395 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Kdaito/microcms-publish/internal/cms"
+	"github.com/Kdaito/microcms-publish/internal/config"
+	"github.com/Kdaito/microcms-publish/internal/git"
 	"github.com/Kdaito/microcms-publish/internal/md"
 )
 
 func main() {
+	err := run(os.Args[1:], os.Getenv, os.Stdout)
+	if err == nil {
+		return
+	}
+
+	log.Print(err)
+	if errors.Is(err, errPartialFailure) {
+		os.Exit(exitPartialFailure)
+	}
+	os.Exit(exitFailure)
+}
+
+// run はコマンドを実行する。テストから実行できるよう、引数・環境変数・計画の出力先を受け取る
+func run(args []string, getenv func(string) string, stdout io.Writer) error {
 	// 環境変数チェック
-	var serviceId = os.Getenv(("SERVICE_ID"))
+	var serviceId = getenv("SERVICE_ID")
 	if serviceId == "" {
-		log.Fatal("SERVICE_ID is not set")
+		return errors.New("SERVICE_ID is not set")
 	}
-	var apiKey = os.Getenv("API_KEY")
+	var apiKey = getenv("API_KEY")
 	if apiKey == "" {
-		log.Fatal("API_KEY is not set")
+		return errors.New("API_KEY is not set")
 	}
-	var endpoint = os.Getenv("ENDPOINT")
+	var endpoint = getenv("ENDPOINT")
 	if endpoint == "" {
-		log.Fatal("ENDPOINT is not set")
+		return errors.New("ENDPOINT is not set")
 	}
 
+	// syncサブコマンドの場合は、差分ではなくすべての記事をMicroCMSと一致させる
+	syncMode := len(args) > 0 && args[0] == "sync"
+	if syncMode {
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet("publish-from-qiita", flag.ContinueOnError)
 	// 差分のファイルを引数から取得する
-	filesString := flag.String("f", "target files", "string array")
-	workspace := flag.String("w", "workspace/path", "workspace path")
-	flag.Parse()
+	filesString := flags.String("f", "target files", "string array")
+	workspace := flags.String("w", "workspace/path", "workspace path")
+	// 削除されたファイルと、その削除前の内容を復元したディレクトリ
+	deletedFilesString := flags.String("d", "", "deleted files (string array)")
+	deletedWorkspace := flags.String("dw", "", "workspace path holding the previous versions of deleted files")
+	enableDelete := flags.Bool("delete", false, "delete microCMS contents of removed files")
+	uploadImages := flags.Bool("upload-images", true, "upload local images to microCMS media and rewrite their URLs")
+	configPath := flags.String("c", "", "config file path (relative to the workspace)")
+	dryRun := flags.Bool("dry-run", false, "print the plan without writing to microCMS")
+	defaultStatus := flags.String("status", md.StatusPublish, "publish state of contents unless set by microcms.status front matter: publish or draft")
+	privatePolicy := flags.String("private", privateSkip, "how to handle private items: skip or draft")
+	maxAttempts := flags.Int("max-attempts", cms.DefaultRetryPolicy.MaxAttempts, "max attempts of a request to microCMS when rate limited or failed with 5xx")
+	syncAll := flags.Bool("all", false, "sync every item under public/ (sync command only)")
+	concurrency := flags.Int("concurrency", 4, "number of items published to microCMS in parallel")
+	itemTimeout := flags.Duration("item-timeout", 30*time.Second, "timeout for publishing or deleting each item")
+	timeout := flags.Duration("timeout", 5*time.Minute, "deadline for the whole run")
+	requestTimeout := flags.Duration("request-timeout", 10*time.Second, "timeout for each request to microCMS, retried within -max-attempts (0 for no timeout)")
+	baseURL := flags.String("base-url", "", "microCMS API URL (default https://<service-id>.microcms.io/api)")
+	managementBaseURL := flags.String("management-base-url", "", "microCMS management API URL (default https://<service-id>.microcms-management.io/api)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
 
 	log.Printf("workspace: %s", *workspace)
 
-	files := strings.Split(*filesString, ",")
+	if *privatePolicy != privateSkip && *privatePolicy != privateDraft {
+		return fmt.Errorf("-private must be %s or %s", privateSkip, privateDraft)
+	}
+	if *defaultStatus != md.StatusPublish && *defaultStatus != md.StatusDraft {
+		return fmt.Errorf("-status must be %s or %s", md.StatusPublish, md.StatusDraft)
+	}
+	if *concurrency < 1 {
+		return errors.New("-concurrency must be positive")
+	}
+	if *timeout <= 0 || *itemTimeout <= 0 {
+		return errors.New("-timeout and -item-timeout must be positive")
+	}
+	if syncMode && !*syncAll {
+		return errors.New("sync requires -all")
+	}
+	if !syncMode && *syncAll {
+		return errors.New("-all is only available with the sync command")
+	}
+
+	// 設定ファイルの読み込み
+	if *configPath != "" && !filepath.IsAbs(*configPath) {
+		*configPath = filepath.Join(*workspace, *configPath)
+	}
+	conf, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	files := splitFiles(*filesString)
+	deletedFiles := splitFiles(*deletedFilesString)
+	if syncMode {
+		// 削除されたファイルはMicroCMSのコンテンツとの比較で判定する
+		files, err = listItemFiles(*workspace)
+		if err != nil {
+			return fmt.Errorf("error listing items: %w", err)
+		}
+		deletedFiles = nil
+		log.Printf("sync: %d file(s) found", len(files))
+	}
 
 	// ファイルから記事情報を取得する
-	parser := md.NewParser(*workspace)
-	items := parser.ParseAllFromQiitaItems(&files)
+	parserOptions := []md.ParserOption{md.WithExcerptLength(conf.Excerpt.Length)}
+	if *dryRun {
+		// プルリクエストで追加された記事は、Qiitaに投稿するまでidがない
+		parserOptions = append(parserOptions, md.AllowMissingID())
+	}
+	parser := md.NewParser(*workspace, parserOptions...)
+	parsed := parser.ParseAllFromQiitaItems(&files)
+	items := parsed.Items
+	parseErrors := parsed.Errors
+
+	// 最初に公開された日時をgitの履歴から取得する
+	if _, ok := conf.Fields[cms.AttrPublishedAt]; ok {
+		for _, item := range items {
+			publishedAt, err := git.FirstCommitTime(*workspace, item.Path)
+			if err != nil {
+				log.Printf("file:[%s] first commit date is not available: %v", item.Path, err)
+				continue
+			}
+			item.PublishedAt = publishedAt
+		}
+	}
+
+	// 削除されたファイルは削除前の内容から記事情報を取得する
+	var deletedItems []*md.Item
+	if len(deletedFiles) > 0 {
+		if !*enableDelete {
+			log.Printf("%d file(s) were removed, but deletion is disabled. Pass -delete to remove them from MicroCMS.", len(deletedFiles))
+		} else {
+			if *deletedWorkspace == "" {
+				return errors.New("-dw is required to delete contents")
+			}
+			deletedParser := md.NewParser(*deletedWorkspace)
+			deletedParsed := deletedParser.ParseAllFromQiitaItems(&deletedFiles)
+			deletedItems = removedItems(deletedParsed.Items, items)
+			parseErrors = append(parseErrors, deletedParsed.Errors...)
+		}
+	}
 
-	if len(items) == 0 {
+	for _, parseErr := range parseErrors {
+		log.Printf("file:[%s] parsing is skipped because: %s: %v", parseErr.Path, parseErr.Stage, parseErr.Err)
+	}
+
+	if len(items) == 0 && len(deletedItems) == 0 && !*dryRun && !syncMode {
 		log.Println("No items found.")
-		return
+		return newSummary(nil, parseErrors).err()
 	}
 
 	httpClient := new(http.Client)
 
+	// 429や5xxの場合は待機して再試行する
+	retryPolicy := cms.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = *maxAttempts
+
 	// クライアントの初期化
+	// 並行して処理する場合もMicroCMSのリクエスト数の上限を超えないよう、すべてのクライアントで共有する
+	rateLimiter := cms.NewRateLimiter(cms.DefaultReadRate, cms.DefaultWriteRate)
+	commonOptions := []cms.Option{
+		cms.WithRetryPolicy(retryPolicy),
+		cms.WithRateLimiter(rateLimiter),
+		cms.WithRequestTimeout(*requestTimeout),
+		cms.WithUserAgent(userAgent),
+	}
+	clientOptions := commonOptions
+	if *baseURL != "" {
+		clientOptions = append(slices.Clone(commonOptions), cms.WithBaseURL(*baseURL))
+	}
 	cmsClient := cms.NewClient(
 		serviceId,
 		apiKey,
 		endpoint,
 		httpClient,
+		append(clientOptions, cms.WithFieldMapping(conf.Fields))...,
 	)
 
-	// コンテキストの作成（タイムアウト付き）
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	managementOptions := commonOptions
+	if *managementBaseURL != "" {
+		managementOptions = append(slices.Clone(commonOptions), cms.WithBaseURL(*managementBaseURL))
+	}
+	uploader := cms.NewMediaUploader(serviceId, apiKey, httpClient, managementOptions...)
+	manager := cms.NewContentManager(serviceId, apiKey, endpoint, httpClient, managementOptions...)
 
-	successItems := make([]string, 0, len(items))
+	// タグを参照フィールドで登録する場合は、タグのAPIからタグのコンテンツIDを取得する
+	tags := &tagSetter{mode: conf.Tags.Mode}
+	if _, ok := conf.Fields[cms.AttrTags]; ok && conf.Tags.Mode == config.TagsModeReference {
+		tagsClient := cms.NewClient(serviceId, apiKey, conf.Tags.Endpoint, httpClient, clientOptions...)
+		tags.resolver = cms.NewTagResolver(tagsClient, conf.Tags.NameField)
+	}
 
-	// 各記事をMicroCMSにアップロードする
-	for _, item := range items {
-		exists, id, err := cmsClient.CheckExists(ctx, item.QiitaID)
-		if err != nil {
-			log.Printf("Error checking existence: %v", err)
-			continue
-		}
+	// 全体の期限を設定する。各リクエスト・各記事のタイムアウトはそれぞれ別に設定する
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
 
-		if exists {
-			log.Printf("Content with ID %s already exists. Updating...", id)
-			err = cmsClient.Update(ctx, id, item.Title, item.Tags, item.QiitaID, item.Content)
-			if err != nil {
-				log.Printf("Error updating content: %v", err)
-			}
-			successItems = append(successItems, item.QiitaID)
+	// 記事のファイルがないコンテンツを削除対象にする
+	if syncMode && *enableDelete {
+		if len(parsed.Errors) > 0 {
+			// 記事情報を取得できなかったファイルのコンテンツを誤って削除しないため
+			log.Printf("Skipping deletion because %d file(s) could not be parsed.", len(parsed.Errors))
 		} else {
-			log.Println("Creating new content...")
-			err = cmsClient.Create(ctx, item.Title, item.Tags, item.QiitaID, item.Content)
+			contents, err := cmsClient.ListQiitaIDs(ctx)
 			if err != nil {
-				log.Printf("Error creating content: %v", err)
+				return fmt.Errorf("error listing contents: %w", err)
 			}
-			successItems = append(successItems, item.QiitaID)
+			deletedItems = orphanedItems(contents, items)
 		}
 	}
 
-	log.Println("Successfully processed items:")
-	for _, id := range successItems {
-		log.Println(id)
+	// 書き込みを行わずに計画だけを出力する
+	if *dryRun {
+		p := &planner{client: cmsClient, uploader: uploader, manager: manager, uploadImages: *uploadImages, mapping: conf.Fields, toc: conf.TOC, tags: tags, privatePolicy: *privatePolicy, defaultStatus: *defaultStatus}
+		steps := make([]planStep, 0, len(files)+len(deletedItems))
+		for _, parseErr := range parseErrors {
+			steps = append(steps, planStep{action: planError, target: parseErr.Path, message: fmt.Sprintf("failed to parse (%s): %v", parseErr.Stage, parseErr.Err)})
+		}
+		for _, item := range items {
+			steps = append(steps, p.planItem(ctx, item))
+		}
+		for _, item := range deletedItems {
+			steps = append(steps, p.planDeletion(ctx, item))
+		}
+
+		if hasError := printPlan(stdout, steps); hasError {
+			return errors.New("the plan has errors")
+		}
+		return nil
+	}
+
+	p := &publisher{
+		client:        cmsClient,
+		uploader:      uploader,
+		manager:       manager,
+		mapping:       conf.Fields,
+		toc:           conf.TOC,
+		tags:          tags,
+		uploadImages:  *uploadImages,
+		privatePolicy: *privatePolicy,
+		defaultStatus: *defaultStatus,
+	}
+
+	// 各記事をMicroCMSにアップロードし、削除されたファイルに対応する記事をMicroCMSから削除する
+	results := processItems(ctx, items, *concurrency, *itemTimeout, p.publish)
+	if abortErr := firstAbort(results); abortErr == nil {
+		results = append(results, processItems(ctx, deletedItems, *concurrency, *itemTimeout, p.delete)...)
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		for _, line := range result.logs {
+			log.Println(line)
+		}
+	}
+	// 中断した場合も、それまでに反映した記事がわかるよう集計を出力する
+	summary := newSummary(results, parseErrors)
+	summary.print()
+	if abortErr := firstAbort(results); abortErr != nil {
+		return abortErr
 	}
-	log.Println("All items processed.")
 	log.Println("Publishing completed.")
+	return summary.err()
+}
+
+// カンマ区切りのファイル一覧を分割する（空要素は除く）
+func splitFiles(filesString string) []string {
+	files := make([]string, 0)
+	for _, file := range strings.Split(filesString, ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// MicroCMSへのリクエストのUser-Agent
+const userAgent = "microcms-publish"
+
+// 限定共有記事の扱い
+const (
+	privateSkip  = "skip"
+	privateDraft = "draft"
+)
+
+// publishStatus は記事の公開設定から、スキップする理由と下書きとして保存するかを返す
+//
+// 限定共有記事は公開しないよう、front matterの `microcms.status` よりも優先する
+func publishStatus(item *md.Item, privatePolicy, defaultStatus string) (string, bool) {
+	switch {
+	case item.IgnorePublish:
+		return "ignorePublish is set", false
+	case item.Private && privatePolicy == privateSkip:
+		return "private item", false
+	case item.Private:
+		return "", true
+	case item.Status != "":
+		return "", item.Status == md.StatusDraft
+	}
+	return "", defaultStatus == md.StatusDraft
+}
+
+// unpublishOnSkip はスキップする記事のコンテンツを下書きに戻すかを返す
+// 公開した記事を後から限定共有にした場合に、MicroCMSで公開されたままにならないようにする
+func unpublishOnSkip(item *md.Item, privatePolicy string) bool {
+	return item.Private && !item.IgnorePublish && privatePolicy == privateSkip && item.QiitaID != ""
+}
+
+// contentStatus は下書きとして保存する場合に、公開中のコンテンツの公開状態をマネジメントAPIで確かめる
+// コンテンツAPIでは、公開中のコンテンツに下書きがある（PUBLISH_AND_DRAFT）か、公開を止めたかを区別できないため
+func contentStatus(ctx context.Context, manager *cms.ContentManager, id string, current cms.ContentStatus, draft bool) (cms.ContentStatus, error) {
+	if !draft || current != cms.StatusPublish {
+		return current, nil
+	}
+	return manager.Status(ctx, id)
+}
+
+// isPublished はコンテンツが公開中かを返す
+func isPublished(status cms.ContentStatus) bool {
+	return status == cms.StatusPublish || status == cms.StatusPublishAndDraft
+}
+
+// statusDiff は既存のコンテンツの公開状態が保存する状態と異なる場合に、その差分を返す
+// 公開を終了したコンテンツは、下書きとして保存する場合は差分にしない
+func statusDiff(current cms.ContentStatus, draft bool) []cms.FieldDiff {
+	want := cms.StatusPublish
+	if draft {
+		want = cms.StatusDraft
+	}
+	if current == want || (draft && current == cms.StatusClosed) {
+		return nil
+	}
+	return []cms.FieldDiff{{Field: "status", Old: string(current), New: string(want)}}
+}
+
+func writeOptions(draft bool) []cms.WriteOption {
+	if draft {
+		return []cms.WriteOption{cms.AsDraft()}
+	}
+	return nil
+}
+
+// abortOnAuthError はAPIキーや権限の誤りの場合、以降の記事もすべて失敗するため実行を中断するエラーを返す
+func abortOnAuthError(err error) error {
+	if cms.IsAuthError(err) {
+		return fmt.Errorf("aborting because the API key is invalid or lacks permission: %w", err)
+	}
+	return nil
+}
+
+// itemFields は記事の属性をMicroCMSに送る値に変換する
+// front matterの値も設定ファイルでフィールドに対応させられるよう含める
+func itemFields(item *md.Item, toc config.TOC) cms.Fields {
+	fields := make(cms.Fields, len(item.FrontMatter)+6)
+	for key, value := range item.FrontMatter {
+		fields[key] = value
+	}
+	fields[cms.AttrTitle] = item.Title
+	fields[cms.AttrTags] = item.Tags
+	fields[cms.AttrQiitaID] = item.QiitaID
+	fields[cms.AttrContent] = item.Content
+	if toc.Embed {
+		fields[cms.AttrContent] = md.RenderTOC(item.Headings) + item.Content
+	}
+	fields[cms.AttrTOC] = tocValue(item.Headings, toc)
+	fields[cms.AttrExcerpt] = item.Excerpt
+	if !item.UpdatedAt.IsZero() {
+		fields[cms.AttrUpdatedAt] = cms.FormatTime(item.UpdatedAt)
+	}
+	if !item.PublishedAt.IsZero() {
+		fields[cms.AttrPublishedAt] = cms.FormatTime(item.PublishedAt)
+	}
+	return fields
 }
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Kdaito/microcms-publish/internal/cms"
+	"github.com/Kdaito/microcms-publish/internal/cms/cmstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAPIKey   = "test-api-key"
+	testEndpoint = "items"
+	// mocks/workspace/public の記事
+	testFiles = "public/first.md,public/second.md,public/private.md"
+)
+
+// newWorkspace は記事を書き換えられるよう、mocks/workspace を一時ディレクトリにコピーする
+func newWorkspace(t *testing.T) string {
+	t.Helper()
+
+	workspace := t.TempDir()
+	require.NoError(t, os.CopyFS(workspace, os.DirFS("../../mocks/workspace")))
+	return workspace
+}
+
+// runCommand はテスト用のMicroCMSに対してコマンドを実行し、計画の出力を返す
+func runCommand(t *testing.T, server *cmstest.Server, apiKey string, args ...string) (string, error) {
+	t.Helper()
+
+	env := map[string]string{
+		"SERVICE_ID": "test-service",
+		"API_KEY":    apiKey,
+		"ENDPOINT":   testEndpoint,
+	}
+	// コンテンツAPIとマネジメントAPIをテスト用のサーバーに向け、再試行の待機でテストが遅くならないよう1回だけ送信する
+	// 同じフラグは後の値が使われるため、サブコマンドの直後に追加してargsで上書きできるようにする
+	defaults := []string{"-base-url", server.BaseURL(), "-management-base-url", server.BaseURL(), "-max-attempts", "1"}
+	if len(args) > 0 && args[0] == "sync" {
+		args = slices.Concat(args[:1], defaults, args[1:])
+	} else {
+		args = append(defaults, args...)
+	}
+
+	var stdout bytes.Buffer
+	err := run(args, func(key string) string { return env[key] }, &stdout)
+	return stdout.String(), err
+}
+
+// writes はコンテンツを書き換えたリクエストを返す
+func writes(server *cmstest.Server) []string {
+	requests := make([]string, 0)
+	for _, request := range server.Requests() {
+		if !strings.HasPrefix(request, "GET ") {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// contentOf はqiitaIdが一致するコンテンツを返す
+func contentOf(t *testing.T, server *cmstest.Server, qiitaID string) map[string]interface{} {
+	t.Helper()
+
+	for _, content := range server.Contents(testEndpoint) {
+		if content["qiitaId"] == qiitaID {
+			return content
+		}
+	}
+	require.Failf(t, "content is not found", "qiitaId: %s", qiitaID)
+	return nil
+}
+
+// newMediaServer はメディアの検索・アップロードだけを行う、テスト用のマネジメントAPIを起動する
+func newMediaServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	urls := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/media":
+			media := make([]map[string]string, 0)
+			for _, url := range urls {
+				if strings.Contains(url, r.URL.Query().Get("fileName")) {
+					media = append(media, map[string]string{"id": url, "url": url})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"media": media, "totalCount": len(media)})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/media":
+			_, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			url := "https://images.microcms-assets.io/assets/test/" + header.Filename
+			urls = append(urls, url)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]string{"url": url})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func qiitaIDs(contents []map[string]interface{}) []string {
+	ids := make([]string, 0, len(contents))
+	for _, content := range contents {
+		ids = append(ids, content["qiitaId"].(string))
+	}
+	return ids
+}
+
+func TestRun_Publish(t *testing.T) {
+	t.Run("正常系_作成した記事は2回目の実行で更新しない", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", testFiles, "-w", workspace)
+		require.NoError(t, err)
+
+		// 限定共有の記事は反映しない
+		assert.ElementsMatch(t, []string{"first0000001", "second000002"}, qiitaIDs(server.Contents(testEndpoint)))
+		content := contentOf(t, server, "first0000001")
+		assert.Equal(t, "最初の記事", content["title"])
+		assert.Equal(t, "Go,Test", content["tags"])
+		assert.Equal(t, "<h2 id=\"はじめに\">はじめに</h2>\n<p>最初の記事です。</p>\n", content["content"])
+		assert.Len(t, writes(server), 2)
+
+		_, err = runCommand(t, server, testAPIKey, "-f", testFiles, "-w", workspace)
+		require.NoError(t, err)
+		assert.Len(t, writes(server), 2)
+	})
+
+	t.Run("正常系_変更した記事を更新する", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", testFiles, "-w", workspace)
+		require.NoError(t, err)
+		id := contentOf(t, server, "first0000001")["id"].(string)
+
+		path := filepath.Join(workspace, "public", "first.md")
+		source, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, bytes.Replace(source, []byte("最初の記事です。"), []byte("更新した記事です。"), 1), 0o644))
+
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace)
+		require.NoError(t, err)
+
+		assert.Equal(t, "PATCH /api/v1/items/"+id, writes(server)[2])
+		assert.Contains(t, contentOf(t, server, "first0000001")["content"], "更新した記事です。")
+	})
+
+	t.Run("正常系_下書きとして保存する", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-status", "draft")
+		require.NoError(t, err)
+
+		id := server.Contents(testEndpoint)[0]["id"].(string)
+		assert.Equal(t, cmstest.StatusDraft, server.Status(testEndpoint, id))
+	})
+
+	t.Run("正常系_下書きの設定を外すと公開する", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-status", "draft")
+		require.NoError(t, err)
+		id := contentOf(t, server, "first0000001")["id"].(string)
+
+		// 下書きのままであれば内容が同じため更新しない
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-status", "draft")
+		require.NoError(t, err)
+		assert.Len(t, writes(server), 1)
+
+		stdout, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, stdout, `~ status: "DRAFT" -> "PUBLISH"`)
+		assert.Contains(t, stdout, "0 to create, 1 to update, 0 to delete, 0 to skip, 0 error(s)")
+
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace)
+		require.NoError(t, err)
+		assert.Equal(t, "PATCH /api/v1/items/"+id, writes(server)[1])
+		assert.Equal(t, cmstest.StatusPublish, server.Status(testEndpoint, id))
+	})
+
+	t.Run("正常系_公開中の記事を下書きに戻す", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace)
+		require.NoError(t, err)
+		id := contentOf(t, server, "first0000001")["id"].(string)
+
+		stdout, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-status", "draft", "-dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, stdout, `~ status: "PUBLISH" -> "DRAFT"`)
+
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-status", "draft")
+		require.NoError(t, err)
+		assert.Equal(t, cmstest.StatusDraft, server.Status(testEndpoint, id))
+
+		// 下書きに戻した後は差分がなく、更新しない
+		stdout, err = runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-status", "draft", "-dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "0 to create, 0 to update, 0 to delete, 1 to skip, 0 error(s)")
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-status", "draft")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"POST /api/v1/" + testEndpoint, "PATCH /api/v1/contents/" + testEndpoint + "/" + id + "/status"}, writes(server))
+	})
+
+	t.Run("正常系_限定共有にした記事は下書きに戻す", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+		id := server.Put(testEndpoint, map[string]interface{}{"title": "限定共有の記事", "qiitaId": "private00003"})
+
+		stdout, err := runCommand(t, server, testAPIKey, "-f", "public/private.md", "-w", workspace, "-dry-run")
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "update  private00003 (id: "+id+") as draft: private item")
+
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/private.md", "-w", workspace)
+		require.NoError(t, err)
+		assert.Equal(t, cmstest.StatusDraft, server.Status(testEndpoint, id))
+		// 内容は変更しない
+		assert.Equal(t, "限定共有の記事", contentOf(t, server, "private00003")["title"])
+
+		// 下書きに戻した後は更新しない
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/private.md", "-w", workspace)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PATCH /api/v1/contents/" + testEndpoint + "/" + id + "/status"}, writes(server))
+	})
+
+	t.Run("正常系_下書きが保存された公開中の記事も下書きに戻す", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+		id := server.Put(testEndpoint, map[string]interface{}{"title": "限定共有の記事", "qiitaId": "private00003"})
+
+		// 公開中のまま下書きだけが保存されている
+		client := cms.NewClient("test-service", testAPIKey, testEndpoint, http.DefaultClient, cms.WithBaseURL(server.BaseURL()))
+		require.NoError(t, client.Update(context.Background(), id, cms.Fields{cms.AttrTitle: "下書き"}, cms.AsDraft()))
+		require.Equal(t, cmstest.StatusPublishAndDraft, server.Status(testEndpoint, id))
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/private.md", "-w", workspace)
+		require.NoError(t, err)
+		assert.Equal(t, cmstest.StatusDraft, server.Status(testEndpoint, id))
+	})
+
+	t.Run("正常系_idのない下書きはスキップし失敗にしない", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/first.md,public/draft.md", "-w", workspace)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first0000001"}, qiitaIDs(server.Contents(testEndpoint)))
+	})
+
+	t.Run("正常系_dry-runでは書き込まない", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		stdout, err := runCommand(t, server, testAPIKey, "-f", testFiles, "-w", workspace, "-dry-run")
+		require.NoError(t, err)
+
+		assert.Contains(t, stdout, "2 to create, 0 to update, 0 to delete, 1 to skip, 0 error(s)")
+		assert.Empty(t, writes(server))
+	})
+
+	t.Run("正常系_dry-runではidのない記事を作成する予定にする", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+		path := filepath.Join(workspace, "public", "first.md")
+		source, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, bytes.Replace(source, []byte("id: first0000001"), []byte("id: null"), 1), 0o644))
+
+		stdout, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace, "-dry-run")
+		require.NoError(t, err)
+
+		assert.Contains(t, stdout, "create  public/first.md: id pending")
+		assert.Contains(t, stdout, "1 to create, 0 to update, 0 to delete, 0 to skip, 0 error(s)")
+		assert.Empty(t, server.Requests())
+	})
+
+	t.Run("正常系_dry-runではアップロード済みの画像のURLで比較する", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		media := newMediaServer(t)
+		workspace := newWorkspace(t)
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, "public", "image.png"), []byte("image"), 0o644))
+		path := filepath.Join(workspace, "public", "first.md")
+		source, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, append(source, []byte("\n![画像](image.png)\n")...), 0o644))
+		mediaArgs := []string{"-f", "public/first.md", "-w", workspace, "-management-base-url", media.URL + "/api"}
+
+		// アップロードされていない画像は、本文の差分ではなくアップロードとして出力する
+		server.Put(testEndpoint, map[string]interface{}{"title": "最初の記事", "qiitaId": "first0000001"})
+		stdout, err := runCommand(t, server, testAPIKey, append(mediaArgs, "-dry-run")...)
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "+ 1 local image(s) will be uploaded")
+		assert.NotContains(t, stdout, "~ content:")
+
+		_, err = runCommand(t, server, testAPIKey, mediaArgs...)
+		require.NoError(t, err)
+		assert.Contains(t, contentOf(t, server, "first0000001")["content"], "https://images.microcms-assets.io/assets/test/")
+
+		// 反映した後は変更なしになる
+		stdout, err = runCommand(t, server, testAPIKey, append(mediaArgs, "-dry-run")...)
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "0 to create, 0 to update, 0 to delete, 1 to skip, 0 error(s)")
+	})
+
+	t.Run("異常系_一部の記事が失敗", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+		// 最初の記事の存在確認を失敗させる
+		server.Fail(http.StatusInternalServerError, "Internal server error")
+
+		_, err := runCommand(t, server, testAPIKey, "-f", testFiles, "-w", workspace, "-concurrency", "1")
+		assert.ErrorIs(t, err, errPartialFailure)
+		assert.Equal(t, []string{"second000002"}, qiitaIDs(server.Contents(testEndpoint)))
+	})
+
+	t.Run("異常系_すべての記事が失敗", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+		server.Fail(http.StatusInternalServerError, "Internal server error")
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", workspace)
+		assert.EqualError(t, err, "no items were published: 1 item(s) failed")
+		assert.Empty(t, server.Contents(testEndpoint))
+	})
+
+	t.Run("異常系_APIキーが不正", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+
+		_, err := runCommand(t, server, "invalid-api-key", "-f", testFiles, "-w", workspace, "-concurrency", "1")
+		assert.ErrorContains(t, err, "the API key is invalid")
+		// 中断した場合も集計を出力する
+		assert.Contains(t, logs.String(), "Summary: 0 created, 0 updated, 0 unchanged, 0 skipped, 0 deleted, 1 failed")
+		// 最初の記事で中断する
+		assert.Len(t, server.Requests(), 1)
+		assert.Empty(t, server.Contents(testEndpoint))
+	})
+}
+
+func TestRun_Delete(t *testing.T) {
+	t.Run("正常系_削除されたファイルの記事を削除する", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		previous := newWorkspace(t)
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/first.md,public/second.md", "-w", previous)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(filepath.Join(workspace, "public", "first.md")))
+
+		_, err = runCommand(t, server, testAPIKey, "-f", "", "-d", "public/first.md", "-dw", previous, "-delete", "-w", workspace)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"second000002"}, qiitaIDs(server.Contents(testEndpoint)))
+	})
+
+	t.Run("正常系_移動したファイルの記事は削除しない", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		previous := newWorkspace(t)
+		workspace := newWorkspace(t)
+
+		_, err := runCommand(t, server, testAPIKey, "-f", "public/first.md", "-w", previous)
+		require.NoError(t, err)
+		id := contentOf(t, server, "first0000001")["id"].(string)
+
+		// gitでは名前の変更ではなく、削除と追加として扱われる程度に書き換える
+		source, err := os.ReadFile(filepath.Join(workspace, "public", "first.md"))
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(filepath.Join(workspace, "public", "first.md")))
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, "public", "renamed.md"), bytes.Replace(source, []byte("最初の記事です。"), []byte("移動した記事です。"), 1), 0o644))
+
+		_, err = runCommand(t, server, testAPIKey, "-f", "public/renamed.md", "-d", "public/first.md", "-dw", previous, "-delete", "-w", workspace)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"POST /api/v1/items", "PATCH /api/v1/items/" + id}, writes(server))
+		assert.Contains(t, contentOf(t, server, "first0000001")["content"], "移動した記事です。")
+	})
+}
+
+func TestRun_Sync(t *testing.T) {
+	t.Run("正常系_記事のないコンテンツを削除する", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+		server.Put(testEndpoint, map[string]interface{}{"title": "削除された記事", "qiitaId": "removed00004"})
+		// qiitaIdのないコンテンツはMicroCMSで作成されたものとして残す
+		server.Put(testEndpoint, map[string]interface{}{"title": "MicroCMSの記事"})
+
+		// idのない下書き（public/draft.md）があっても削除する
+		_, err := runCommand(t, server, testAPIKey, "sync", "-all", "-delete", "-w", workspace)
+		require.NoError(t, err)
+
+		contents := server.Contents(testEndpoint)
+		assert.Len(t, contents, 3)
+		assert.Equal(t, "MicroCMSの記事", contents[0]["title"])
+		assert.ElementsMatch(t, []string{"first0000001", "second000002"}, qiitaIDs(contents[1:]))
+	})
+
+	t.Run("異常系_記事情報を取得できないファイルがある場合は削除しない", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+		workspace := newWorkspace(t)
+		server.Put(testEndpoint, map[string]interface{}{"title": "削除された記事", "qiitaId": "removed00004"})
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, "public", "broken.md"), []byte("front matterがない記事"), 0o644))
+
+		_, err := runCommand(t, server, testAPIKey, "sync", "-all", "-delete", "-w", workspace)
+		assert.ErrorIs(t, err, errPartialFailure)
+		assert.ElementsMatch(t, []string{"removed00004", "first0000001", "second000002"}, qiitaIDs(server.Contents(testEndpoint)))
+	})
+
+	t.Run("異常系_-allがない", func(t *testing.T) {
+		server := cmstest.NewServer(testAPIKey, testEndpoint)
+		defer server.Close()
+
+		_, err := runCommand(t, server, testAPIKey, "sync", "-w", newWorkspace(t))
+		assert.EqualError(t, err, "sync requires -all")
+	})
+}
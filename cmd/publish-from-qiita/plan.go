@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/Kdaito/microcms-publish/internal/cms"
+	"github.com/Kdaito/microcms-publish/internal/config"
+	"github.com/Kdaito/microcms-publish/internal/md"
+)
+
+type planAction string
+
+const (
+	planCreate planAction = "create"
+	planUpdate planAction = "update"
+	planDelete planAction = "delete"
+	planSkip   planAction = "skip"
+	planError  planAction = "error"
+)
+
+// planStep は1件の記事に対して実行される予定の操作
+type planStep struct {
+	action planAction
+	// 記事のQiita ID、または記事を取得できなかったファイル
+	target string
+	// MicroCMSのコンテンツID
+	id    string
+	diffs []cms.FieldDiff
+	// アップロードされていないため、反映時にアップロードされる画像の数
+	images int
+	// 作成される予定のタグ
+	newTags []string
+	draft   bool
+	message string
+}
+
+// planner は書き込みを行わずに、記事ごとの操作を計画する
+type planner struct {
+	client        *cms.Client
+	uploader      *cms.MediaUploader
+	manager       *cms.ContentManager
+	uploadImages  bool
+	mapping       cms.FieldMapping
+	toc           config.TOC
+	tags          *tagSetter
+	privatePolicy string
+	defaultStatus string
+}
+
+func (p *planner) planItem(ctx context.Context, item *md.Item) planStep {
+	step := planStep{target: itemName(item)}
+
+	skipReason, draft := publishStatus(item, p.privatePolicy, p.defaultStatus)
+	if skipReason != "" {
+		step.action = planSkip
+		step.message = skipReason
+		if unpublishOnSkip(item, p.privatePolicy) {
+			p.planUnpublish(ctx, item, &step)
+		}
+		return step
+	}
+	step.draft = draft
+
+	// アップロード済みの画像は反映時と同じURLに置き換え、本文の差分を正しく比較する
+	if p.uploadImages {
+		pending, err := p.resolveUploadedImages(ctx, item)
+		if err != nil {
+			step.action = planError
+			step.message = fmt.Sprintf("looking up images: %v", err)
+			return step
+		}
+		step.images = pending
+	}
+
+	fields := itemFields(item, p.toc)
+	newTags, err := p.tags.plan(ctx, fields, item)
+	if err != nil {
+		step.action = planError
+		step.message = fmt.Sprintf("resolving tags: %v", err)
+		return step
+	}
+	step.newTags = newTags
+
+	// Qiitaに投稿する前の記事はidが決まっていないため、作成する予定にする
+	if item.QiitaID == "" {
+		step.action = planCreate
+		step.message = "id pending"
+		return step
+	}
+
+	id, existing, current, err := p.client.Find(ctx, item.QiitaID)
+	if err != nil {
+		step.action = planError
+		step.message = fmt.Sprintf("checking existence: %v", err)
+		return step
+	}
+
+	if id == "" {
+		step.action = planCreate
+		return step
+	}
+
+	step.id = id
+	current, err = contentStatus(ctx, p.manager, id, current, draft)
+	if err != nil {
+		step.action = planError
+		step.message = fmt.Sprintf("checking status: %v", err)
+		return step
+	}
+	step.diffs = append(p.mapping.Diff(existing, fields), statusDiff(current, draft)...)
+	if step.images > 0 {
+		// アップロード後のURLは決まっていないため、本文は差分に含めず、画像のアップロードとして出力する
+		step.diffs = slices.DeleteFunc(step.diffs, func(diff cms.FieldDiff) bool {
+			return diff.Field == p.mapping[cms.AttrContent]
+		})
+	}
+	if len(step.diffs) == 0 && step.images == 0 {
+		step.action = planSkip
+		step.message = "unchanged"
+		return step
+	}
+
+	step.action = planUpdate
+	return step
+}
+
+// resolveUploadedImages はアップロード済みのローカルの画像のsrcをメディアのURLに置き換え、
+// アップロードされていない画像の数を返す
+func (p *planner) resolveUploadedImages(ctx context.Context, item *md.Item) (int, error) {
+	pending := 0
+	urls := make(map[string]string)
+	for _, image := range item.Images {
+		if !image.IsLocal() {
+			continue
+		}
+
+		url, err := p.uploader.LookupFile(ctx, image.Path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to look up %s: %w", image.Src, err)
+		}
+		if url == "" {
+			pending++
+			continue
+		}
+		urls[image.Src] = url
+	}
+
+	item.ReplaceImageSources(urls)
+	return pending, nil
+}
+
+// planUnpublish は公開中のコンテンツを下書きに戻す予定にする
+func (p *planner) planUnpublish(ctx context.Context, item *md.Item, step *planStep) {
+	id, _, current, err := p.client.Find(ctx, item.QiitaID)
+	if err != nil {
+		step.action = planError
+		step.message = fmt.Sprintf("checking existence: %v", err)
+		return
+	}
+	if id == "" {
+		return
+	}
+	current, err = contentStatus(ctx, p.manager, id, current, true)
+	if err != nil {
+		step.action = planError
+		step.message = fmt.Sprintf("checking status: %v", err)
+		return
+	}
+	if !isPublished(current) {
+		return
+	}
+
+	step.action = planUpdate
+	step.id = id
+	step.draft = true
+	step.diffs = statusDiff(current, true)
+}
+
+func (p *planner) planDeletion(ctx context.Context, item *md.Item) planStep {
+	step := planStep{target: itemName(item)}
+
+	// Qiitaに投稿していない記事は、対応するコンテンツがない
+	if item.QiitaID == "" {
+		step.action = planSkip
+		step.message = "no qiita id"
+		return step
+	}
+
+	exists, id, err := p.client.CheckExists(ctx, item.QiitaID)
+	if err != nil {
+		step.action = planError
+		step.message = fmt.Sprintf("checking existence: %v", err)
+		return step
+	}
+
+	if !exists {
+		step.action = planSkip
+		step.message = "does not exist"
+		return step
+	}
+
+	step.action = planDelete
+	step.id = id
+	return step
+}
+
+// printPlan は計画を出力し、エラーが含まれているかを返す
+func printPlan(w io.Writer, steps []planStep) bool {
+	counts := make(map[planAction]int)
+
+	fmt.Fprintln(w, "Plan:")
+	for _, step := range steps {
+		counts[step.action]++
+
+		line := fmt.Sprintf("  %-6s  %s", step.action, step.target)
+		if step.id != "" {
+			line += fmt.Sprintf(" (id: %s)", step.id)
+		}
+		if step.draft {
+			line += " as draft"
+		}
+		if step.message != "" {
+			line += ": " + step.message
+		}
+		fmt.Fprintln(w, line)
+
+		for _, diff := range step.diffs {
+			fmt.Fprintf(w, "      ~ %s: %s -> %s\n", diff.Field, formatValue(diff.Old), formatValue(diff.New))
+		}
+		if step.action == planCreate || step.action == planUpdate {
+			if step.images > 0 {
+				fmt.Fprintf(w, "      + %d local image(s) will be uploaded\n", step.images)
+			}
+			for _, tag := range step.newTags {
+				fmt.Fprintf(w, "      + tag %q will be created\n", tag)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "%d to create, %d to update, %d to delete, %d to skip, %d error(s)\n",
+		counts[planCreate], counts[planUpdate], counts[planDelete], counts[planSkip], counts[planError])
+
+	return counts[planError] > 0
+}
+
+// 本文などの長い値は先頭だけを表示する
+func formatValue(value interface{}) string {
+	const maxLength = 40
+
+	if value == nil {
+		return "(none)"
+	}
+
+	s := fmt.Sprintf("%v", value)
+	if str, ok := value.(string); ok {
+		s = str
+	}
+	if runes := []rune(s); len(runes) > maxLength {
+		s = string(runes[:maxLength]) + "..."
+	}
+	return fmt.Sprintf("%q", s)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kdaito/microcms-publish/internal/cms"
+	"github.com/Kdaito/microcms-publish/internal/config"
+	"github.com/Kdaito/microcms-publish/internal/md"
+)
+
+// 記事の処理結果
+type itemStatus int
+
+const (
+	statusFailed itemStatus = iota
+	statusCreated
+	statusUpdated
+	statusUnchanged
+	statusSkipped
+	statusDeleted
+)
+
+// itemResult は1件の記事の処理結果
+// 並行して処理した記事のログが混ざらないよう、ログを保持して記事の順に出力する
+type itemResult struct {
+	qiitaID string
+	status  itemStatus
+	logs    []string
+	// 処理に失敗した場合のエラー
+	err error
+	// 記事・リクエストのタイムアウトや全体の期限により、処理を打ち切った
+	timedOut bool
+	// 以降の記事の処理を中断するエラー
+	abort error
+}
+
+func (r *itemResult) logf(format string, args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+// fail はエラーを記録し、APIキーや権限の誤りの場合は中断する
+func (r *itemResult) fail(format string, err error) {
+	r.logf(format, err)
+	r.status = statusFailed
+	r.err = err
+	r.timedOut = errors.Is(err, context.DeadlineExceeded)
+	r.abort = abortOnAuthError(err)
+}
+
+// itemName はログや集計に表示する記事の名前を返す
+// Qiitaに投稿していない下書きはidがないため、ファイルのパスを使う
+func itemName(item *md.Item) string {
+	if item.QiitaID == "" {
+		return item.Path
+	}
+	return item.QiitaID
+}
+
+// publisher は記事をMicroCMSに反映する
+type publisher struct {
+	client        *cms.Client
+	uploader      *cms.MediaUploader
+	manager       *cms.ContentManager
+	mapping       cms.FieldMapping
+	toc           config.TOC
+	tags          *tagSetter
+	uploadImages  bool
+	privatePolicy string
+	defaultStatus string
+}
+
+// publish は記事を作成・更新する
+func (p *publisher) publish(ctx context.Context, item *md.Item) *itemResult {
+	r := &itemResult{qiitaID: itemName(item)}
+
+	skipReason, draft := publishStatus(item, p.privatePolicy, p.defaultStatus)
+	if skipReason != "" {
+		r.logf("%s is skipped because: %s", itemName(item), skipReason)
+		r.status = statusSkipped
+		if unpublishOnSkip(item, p.privatePolicy) {
+			p.unpublish(ctx, item, r)
+		}
+		return r
+	}
+
+	// ローカルの画像をMicroCMSのメディアにアップロードし、URLを置き換える
+	if p.uploadImages {
+		if err := p.uploadLocalImages(ctx, item, r); err != nil {
+			r.fail("Error uploading images: %v", err)
+			return r
+		}
+	}
+
+	id, existing, current, err := p.client.Find(ctx, item.QiitaID)
+	if err != nil {
+		r.fail("Error checking existence: %v", err)
+		return r
+	}
+
+	fields := itemFields(item, p.toc)
+	if err := p.tags.set(ctx, fields, item); err != nil {
+		r.fail("Error resolving tags: %v", err)
+		return r
+	}
+
+	if id != "" {
+		current, err = contentStatus(ctx, p.manager, id, current, draft)
+		if err != nil {
+			r.fail("Error checking status: %v", err)
+			return r
+		}
+
+		// 内容と公開状態が変わっていなければ更新しない（MicroCMSのWebhookを無駄に発火させないため）
+		diffs := p.mapping.Diff(existing, fields)
+		if len(diffs) == 0 && len(statusDiff(current, draft)) == 0 {
+			r.logf("Content with ID %s is unchanged. Skipping...", id)
+			r.status = statusUnchanged
+			return r
+		}
+
+		// 公開中のコンテンツは下書きとして更新しても公開されたままのため、先に下書きに戻す
+		if draft && isPublished(current) {
+			r.logf("Content with ID %s is published. Reverting to draft...", id)
+			if err := p.manager.SetStatus(ctx, id, cms.StatusDraft); err != nil {
+				r.fail("Error reverting content to draft: %v", err)
+				return r
+			}
+			r.status = statusUpdated
+			if len(diffs) == 0 {
+				return r
+			}
+		}
+
+		r.logf("Content with ID %s already exists. Updating...", id)
+		if err := p.client.Update(ctx, id, fields, writeOptions(draft)...); err != nil {
+			r.fail("Error updating content: %v", err)
+			return r
+		}
+		r.status = statusUpdated
+		return r
+	}
+
+	r.logf("Creating new content...")
+	if _, err := p.client.Create(ctx, fields, writeOptions(draft)...); err != nil {
+		r.fail("Error creating content: %v", err)
+		return r
+	}
+	r.status = statusCreated
+	return r
+}
+
+// unpublish は公開中のコンテンツを下書きに戻す
+func (p *publisher) unpublish(ctx context.Context, item *md.Item, r *itemResult) {
+	id, _, current, err := p.client.Find(ctx, item.QiitaID)
+	if err != nil {
+		r.fail("Error checking existence: %v", err)
+		return
+	}
+	if id == "" {
+		return
+	}
+	current, err = contentStatus(ctx, p.manager, id, current, true)
+	if err != nil {
+		r.fail("Error checking status: %v", err)
+		return
+	}
+	if !isPublished(current) {
+		return
+	}
+
+	r.logf("Content with ID %s is published. Reverting to draft...", id)
+	if err := p.manager.SetStatus(ctx, id, cms.StatusDraft); err != nil {
+		r.fail("Error reverting content to draft: %v", err)
+		return
+	}
+	r.status = statusUpdated
+}
+
+// delete は削除されたファイルに対応する記事をMicroCMSから削除する
+func (p *publisher) delete(ctx context.Context, item *md.Item) *itemResult {
+	r := &itemResult{qiitaID: itemName(item)}
+
+	// Qiitaに投稿していない記事は、対応するコンテンツがない
+	if item.QiitaID == "" {
+		r.logf("%s has no qiita id. Skipping deletion.", item.Path)
+		r.status = statusSkipped
+		return r
+	}
+
+	exists, id, err := p.client.CheckExists(ctx, item.QiitaID)
+	if err != nil {
+		r.fail("Error checking existence: %v", err)
+		return r
+	}
+
+	if !exists {
+		r.logf("Content with qiitaId %s does not exist. Skipping deletion.", item.QiitaID)
+		r.status = statusSkipped
+		return r
+	}
+
+	r.logf("Content with ID %s was removed. Deleting...", id)
+	if err := p.client.Delete(ctx, id); err != nil {
+		r.fail("Error deleting content: %v", err)
+		return r
+	}
+	r.status = statusDeleted
+	return r
+}
+
+func (p *publisher) uploadLocalImages(ctx context.Context, item *md.Item, r *itemResult) error {
+	urls := make(map[string]string)
+	for _, image := range item.Images {
+		if !image.IsLocal() {
+			continue
+		}
+
+		url, err := p.uploader.UploadFile(ctx, image.Path)
+		if err != nil {
+			return fmt.Errorf("failed to upload %s: %w", image.Src, err)
+		}
+		r.logf("Uploaded %s to %s", image.Src, url)
+		urls[image.Src] = url
+	}
+
+	item.ReplaceImageSources(urls)
+	return nil
+}
+
+// processItems は記事を最大concurrency件ずつ並行して処理し、結果を記事の順に返す
+// 記事ごとにtimeoutで処理を打ち切る。中断するエラーが発生した場合は、未着手の記事を処理せずnilのままにする
+// 全体の期限を過ぎた場合は、未着手の記事をタイムアウトとして返す
+func processItems(parent context.Context, items []*md.Item, concurrency int, timeout time.Duration, process func(context.Context, *md.Item) *itemResult) []*itemResult {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	results := make([]*itemResult, len(items))
+	slots := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			itemCtx, cancelItem := context.WithTimeout(ctx, timeout)
+			defer cancelItem()
+
+			result := process(itemCtx, item)
+			if result.abort != nil {
+				cancel()
+			}
+			results[i] = result
+		}()
+	}
+
+	wg.Wait()
+
+	if err := parent.Err(); err != nil {
+		for i, item := range items {
+			if results[i] == nil {
+				results[i] = &itemResult{qiitaID: itemName(item), err: err, timedOut: errors.Is(err, context.DeadlineExceeded)}
+				results[i].logf("%s was not processed: %v", itemName(item), err)
+			}
+		}
+	}
+	return results
+}
+
+// firstAbort は記事の順で最初の、処理を中断したエラーを返す
+func firstAbort(results []*itemResult) error {
+	for _, result := range results {
+		if result != nil && result.abort != nil {
+			return result.abort
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kdaito/microcms-publish/internal/md"
+	"github.com/stretchr/testify/assert"
+)
+
+func newItems(n int) []*md.Item {
+	items := make([]*md.Item, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, &md.Item{QiitaID: fmt.Sprintf("item-%d", i)})
+	}
+	return items
+}
+
+func TestProcessItems(t *testing.T) {
+	t.Run("正常系_記事の順に結果を返す", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		results := processItems(context.Background(), newItems(10), 3, time.Second, func(ctx context.Context, item *md.Item) *itemResult {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			// 後の記事ほど早く終わる
+			var i int
+			fmt.Sscanf(item.QiitaID, "item-%d", &i)
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			return &itemResult{qiitaID: item.QiitaID}
+		})
+
+		for i, result := range results {
+			assert.Equal(t, fmt.Sprintf("item-%d", i), result.qiitaID)
+		}
+		assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	})
+
+	t.Run("正常系_記事ごとにタイムアウトする", func(t *testing.T) {
+		results := processItems(context.Background(), newItems(2), 2, 10*time.Millisecond, func(ctx context.Context, item *md.Item) *itemResult {
+			<-ctx.Done()
+			return &itemResult{qiitaID: item.QiitaID, logs: []string{ctx.Err().Error()}}
+		})
+
+		for _, result := range results {
+			assert.Equal(t, []string{context.DeadlineExceeded.Error()}, result.logs)
+		}
+	})
+
+	t.Run("異常系_全体の期限を過ぎた場合は未着手の記事もタイムアウトにする", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		results := processItems(ctx, newItems(3), 1, time.Second, func(ctx context.Context, item *md.Item) *itemResult {
+			<-ctx.Done()
+			result := &itemResult{qiitaID: item.QiitaID}
+			result.fail("Error: %v", ctx.Err())
+			return result
+		})
+
+		for i, result := range results {
+			assert.Equal(t, fmt.Sprintf("item-%d", i), result.qiitaID)
+			assert.True(t, result.timedOut)
+			assert.ErrorIs(t, result.err, context.DeadlineExceeded)
+		}
+		assert.Equal(t, []string{"item-2 was not processed: context deadline exceeded"}, results[2].logs)
+	})
+
+	t.Run("異常系_失敗とタイムアウトを区別する", func(t *testing.T) {
+		results := processItems(context.Background(), newItems(2), 2, 10*time.Millisecond, func(ctx context.Context, item *md.Item) *itemResult {
+			result := &itemResult{qiitaID: item.QiitaID}
+			if item.QiitaID == "item-0" {
+				<-ctx.Done()
+				result.fail("Error: %v", ctx.Err())
+			} else {
+				result.fail("Error: %v", errors.New("failed"))
+			}
+			return result
+		})
+
+		assert.True(t, results[0].timedOut)
+		assert.False(t, results[1].timedOut)
+		assert.EqualError(t, results[1].err, "failed")
+	})
+
+	t.Run("異常系_中断した場合は以降の記事を処理しない", func(t *testing.T) {
+		abort := errors.New("aborted")
+		results := processItems(context.Background(), newItems(5), 1, time.Second, func(ctx context.Context, item *md.Item) *itemResult {
+			result := &itemResult{qiitaID: item.QiitaID}
+			if item.QiitaID == "item-1" {
+				result.abort = abort
+			}
+			return result
+		})
+
+		assert.NotNil(t, results[0])
+		assert.Equal(t, abort, results[1].abort)
+		assert.Nil(t, results[2])
+		assert.Equal(t, abort, firstAbort(results))
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Kdaito/microcms-publish/internal/md"
+)
+
+// 終了コード
+const (
+	exitFailure = 1
+	// 一部の記事だけ反映できた場合
+	exitPartialFailure = 2
+)
+
+// errPartialFailure は一部の記事の反映に失敗したことを表す
+var errPartialFailure = errors.New("some items failed")
+
+// summary は記事の処理結果を種類ごとに集計する
+type summary struct {
+	created   []string
+	updated   []string
+	unchanged []string
+	skipped   []string
+	deleted   []string
+	timedOut  []*itemResult
+	failed    []*itemResult
+
+	parseErrors []*md.ParseError
+}
+
+func newSummary(results []*itemResult, parseErrors []*md.ParseError) *summary {
+	s := &summary{parseErrors: parseErrors}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		switch result.status {
+		case statusCreated:
+			s.created = append(s.created, result.qiitaID)
+		case statusUpdated:
+			s.updated = append(s.updated, result.qiitaID)
+		case statusUnchanged:
+			s.unchanged = append(s.unchanged, result.qiitaID)
+		case statusSkipped:
+			s.skipped = append(s.skipped, result.qiitaID)
+		case statusDeleted:
+			s.deleted = append(s.deleted, result.qiitaID)
+		case statusFailed:
+			if result.timedOut {
+				s.timedOut = append(s.timedOut, result)
+			} else {
+				s.failed = append(s.failed, result)
+			}
+		}
+	}
+	return s
+}
+
+// failures は反映・削除できなかった記事と、記事情報を取得できなかったファイルの数を返す
+func (s *summary) failures() int {
+	return len(s.timedOut) + len(s.failed) + len(s.parseErrors)
+}
+
+// succeeded はMicroCMSと一致させられた記事の数を返す（スキップした記事は含めない）
+func (s *summary) succeeded() int {
+	return len(s.created) + len(s.updated) + len(s.unchanged) + len(s.deleted)
+}
+
+func (s *summary) print() {
+	printIDs("Created items:", s.created)
+	printIDs("Updated items:", s.updated)
+	printIDs("Unchanged items:", s.unchanged)
+	printIDs("Skipped items:", s.skipped)
+	printIDs("Deleted items:", s.deleted)
+	printFailures("Timed out items:", s.timedOut)
+	printFailures("Failed items:", s.failed)
+	if len(s.parseErrors) > 0 {
+		log.Println("Failed to parse:")
+		for _, parseErr := range s.parseErrors {
+			log.Printf("%s (%s): %v", parseErr.Path, parseErr.Stage, parseErr.Err)
+		}
+	}
+
+	log.Printf("Summary: %d created, %d updated, %d unchanged, %d skipped, %d deleted, %d failed, %d timed out, %d failed to parse",
+		len(s.created), len(s.updated), len(s.unchanged), len(s.skipped), len(s.deleted), len(s.failed), len(s.timedOut), len(s.parseErrors))
+}
+
+// err は失敗した記事がある場合にエラーを返す
+// 1件も反映できなかった場合と区別するため、一部だけ反映できた場合はerrPartialFailureを返す
+func (s *summary) err() error {
+	failures := s.failures()
+	if failures == 0 {
+		return nil
+	}
+	if s.succeeded() == 0 {
+		return fmt.Errorf("no items were published: %d item(s) failed", failures)
+	}
+	return fmt.Errorf("%w: %d item(s) failed, %d item(s) succeeded", errPartialFailure, failures, s.succeeded())
+}
+
+func printIDs(title string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	log.Println(title)
+	for _, id := range ids {
+		log.Println(id)
+	}
+}
+
+func printFailures(title string, results []*itemResult) {
+	if len(results) == 0 {
+		return
+	}
+	log.Println(title)
+	for _, result := range results {
+		log.Printf("%s: %v", result.qiitaID, result.err)
+	}
+}
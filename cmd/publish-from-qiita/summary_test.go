@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Kdaito/microcms-publish/internal/md"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	failed := &itemResult{qiitaID: "failed", status: statusFailed, err: errors.New("failed")}
+	timedOut := &itemResult{qiitaID: "timedOut", status: statusFailed, err: context.DeadlineExceeded, timedOut: true}
+	parseError := &md.ParseError{Path: "public/invalid.md", Stage: md.StageFrontMatter, Err: errors.New("invalid")}
+
+	tests := []struct {
+		name               string
+		results            []*itemResult
+		parseErrors        []*md.ParseError
+		expectedSummary    *summary
+		expectedError      string
+		expectedPartialErr bool
+	}{
+		{
+			name: "正常系",
+			results: []*itemResult{
+				{qiitaID: "created", status: statusCreated},
+				{qiitaID: "updated", status: statusUpdated},
+				{qiitaID: "unchanged", status: statusUnchanged},
+				{qiitaID: "skipped", status: statusSkipped},
+				{qiitaID: "deleted", status: statusDeleted},
+				// 中断により処理しなかった記事
+				nil,
+			},
+			expectedSummary: &summary{
+				created:   []string{"created"},
+				updated:   []string{"updated"},
+				unchanged: []string{"unchanged"},
+				skipped:   []string{"skipped"},
+				deleted:   []string{"deleted"},
+			},
+		},
+		{
+			name: "異常系_一部の記事が失敗",
+			results: []*itemResult{
+				{qiitaID: "created", status: statusCreated},
+				failed,
+				timedOut,
+			},
+			parseErrors: []*md.ParseError{parseError},
+			expectedSummary: &summary{
+				created:     []string{"created"},
+				failed:      []*itemResult{failed},
+				timedOut:    []*itemResult{timedOut},
+				parseErrors: []*md.ParseError{parseError},
+			},
+			expectedError:      "some items failed: 3 item(s) failed, 1 item(s) succeeded",
+			expectedPartialErr: true,
+		},
+		{
+			name: "異常系_すべての記事が失敗",
+			results: []*itemResult{
+				{qiitaID: "skipped", status: statusSkipped},
+				failed,
+			},
+			expectedSummary: &summary{
+				skipped: []string{"skipped"},
+				failed:  []*itemResult{failed},
+			},
+			expectedError: "no items were published: 1 item(s) failed",
+		},
+		{
+			name:            "異常系_記事情報を取得できない",
+			parseErrors:     []*md.ParseError{parseError},
+			expectedSummary: &summary{parseErrors: []*md.ParseError{parseError}},
+			expectedError:   "no items were published: 1 item(s) failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSummary(tt.results, tt.parseErrors)
+			assert.Equal(t, tt.expectedSummary, s)
+
+			err := s.err()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedPartialErr, errors.Is(err, errPartialFailure))
+		})
+	}
+}
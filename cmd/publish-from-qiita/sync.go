@@ -0,0 +1,96 @@
+package main
+
+import (
+	"io/fs"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Kdaito/microcms-publish/internal/md"
+)
+
+// itemsDir はqiita-cliが記事を管理するディレクトリ
+const itemsDir = "public"
+
+// listItemFiles はワークスペースの `public` 以下にあるすべての記事のファイルを、
+// ワークスペースからの相対パスで返す
+//
+// qiita-cliがQiita上の内容を保存する `public/.remote` などのドットで始まるディレクトリは含めない
+func listItemFiles(workspace string) ([]string, error) {
+	root := filepath.Join(workspace, itemsDir)
+
+	files := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		file, err := filepath.Rel(workspace, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(file))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// orphanedItems はMicroCMSにあるが、対応する記事のファイルがないコンテンツを返す
+// 削除処理で使用するため、qiitaIdだけを設定した記事として返す
+// ファイルがある記事は、ignorePublishなどで反映しない記事でも削除の対象にしない
+func orphanedItems(contents map[string]string, items []*md.Item) []*md.Item {
+	exists := make(map[string]bool, len(items))
+	for _, item := range items {
+		exists[item.QiitaID] = true
+	}
+
+	qiitaIDs := make([]string, 0)
+	for qiitaID := range contents {
+		if !exists[qiitaID] {
+			qiitaIDs = append(qiitaIDs, qiitaID)
+		}
+	}
+	sort.Strings(qiitaIDs)
+
+	orphaned := make([]*md.Item, 0, len(qiitaIDs))
+	for _, qiitaID := range qiitaIDs {
+		orphaned = append(orphaned, &md.Item{QiitaID: qiitaID})
+	}
+	return orphaned
+}
+
+// removedItems は削除されたファイルの記事のうち、他のファイルで使われていない記事を返す
+// ファイルを移動した場合に、移動先のファイルで更新したコンテンツを削除しないようにする
+func removedItems(deleted, items []*md.Item) []*md.Item {
+	exists := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.QiitaID != "" {
+			exists[item.QiitaID] = true
+		}
+	}
+
+	removed := make([]*md.Item, 0, len(deleted))
+	for _, item := range deleted {
+		if exists[item.QiitaID] {
+			log.Printf("file:[%s] was removed, but its qiitaId %s is used by another file. Skipping deletion.", item.Path, item.QiitaID)
+			continue
+		}
+		removed = append(removed, item)
+	}
+	return removed
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Kdaito/microcms-publish/internal/cms"
+	"github.com/Kdaito/microcms-publish/internal/config"
+	"github.com/Kdaito/microcms-publish/internal/md"
+)
+
+// tagSetter は設定ファイルの `tags.mode` に合わせて、送信するタグの値を設定する
+type tagSetter struct {
+	mode string
+	// referenceの場合のみ設定する
+	resolver *cms.TagResolver
+}
+
+// set はタグの値を設定する。referenceの場合、存在しないタグはタグのAPIに作成する
+func (s *tagSetter) set(ctx context.Context, fields cms.Fields, item *md.Item) error {
+	switch s.mode {
+	case config.TagsModeMultiSelect:
+		fields[cms.AttrTags] = tagNames(item)
+	case config.TagsModeReference:
+		if s.resolver == nil {
+			return nil
+		}
+		ids, err := s.resolver.Resolve(ctx, tagNames(item))
+		if err != nil {
+			return err
+		}
+		fields[cms.AttrTags] = ids
+	}
+	return nil
+}
+
+// plan はタグを作成せずにタグの値を設定し、作成される予定のタグを返す
+func (s *tagSetter) plan(ctx context.Context, fields cms.Fields, item *md.Item) ([]string, error) {
+	if s.mode != config.TagsModeReference || s.resolver == nil {
+		return nil, s.set(ctx, fields, item)
+	}
+
+	ids, missing, err := s.resolver.Lookup(ctx, tagNames(item))
+	if err != nil {
+		return nil, err
+	}
+	fields[cms.AttrTags] = ids
+	return missing, nil
+}
+
+// タグがない場合も、既存のタグを外せるよう空の配列を送信する
+func tagNames(item *md.Item) []string {
+	return append(make([]string, 0, len(item.TagNames)), item.TagNames...)
+}
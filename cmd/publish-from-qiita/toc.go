@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/Kdaito/microcms-publish/internal/config"
+	"github.com/Kdaito/microcms-publish/internal/md"
+)
+
+// tocValue は目次を、設定ファイルの `toc.format` に合わせた値に変換する
+func tocValue(headings []*md.Heading, conf config.TOC) interface{} {
+	if conf.Format != config.TOCFormatRepeated {
+		return headings
+	}
+
+	// 繰り返しフィールドは入れ子にできないため、見出しを出現順に並べてレベルで階層を表す
+	values := make([]map[string]interface{}, 0)
+	var walk func(headings []*md.Heading)
+	walk = func(headings []*md.Heading) {
+		for _, heading := range headings {
+			values = append(values, map[string]interface{}{
+				"fieldId": conf.CustomFieldID,
+				"anchor":  heading.ID,
+				"text":    heading.Text,
+				"level":   heading.Level,
+			})
+			walk(heading.Children)
+		}
+	}
+	walk(headings)
+	return values
+}
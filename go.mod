@@ -6,11 +6,11 @@ require (
 	github.com/ghodss/yaml v1.0.0
 	github.com/stretchr/testify v1.10.0
 	github.com/yuin/goldmark v1.7.12
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
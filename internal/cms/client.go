@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 type HTTPDoer interface {
@@ -17,18 +20,77 @@ type HTTPDoer interface {
 type Client struct {
 	apiKey     string
 	httpClient HTTPDoer
-	baseURL    string
+	// APIのURL（`https://<サービスID>.microcms.io/api`）
+	apiBase    string
+	apiVersion string
+	endpoint   string
+	// エンドポイントのURL（apiBase・apiVersion・endpointから組み立てる）
+	baseURL   string
+	userAgent string
+	mapping   FieldMapping
+
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	// 1回のリクエストのタイムアウト（0の場合はctxの期限まで待つ）
+	requestTimeout time.Duration
+	sleep          func(context.Context, time.Duration) error
+}
+
+// 既定のAPIのバージョン
+const defaultAPIVersion = "v1"
+
+type Option func(*Client)
+
+// WithFieldMapping は記事の属性とMicroCMSのフィールドIDの対応表を指定する
+func WithFieldMapping(mapping FieldMapping) Option {
+	return func(c *Client) {
+		c.mapping = mapping
+	}
+}
+
+// WithBaseURL はAPIのURL（既定は `https://<サービスID>.microcms.io/api`）を指定する
+// プロキシやテスト用のサーバーに接続する場合に使用する
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.apiBase = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithAPIVersion はAPIのバージョン（既定は `v1`）を指定する
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = strings.Trim(version, "/")
+	}
+}
+
+// WithUserAgent はリクエストのUser-Agentヘッダーを指定する
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRequestTimeout は1回のリクエストのタイムアウトを指定する
+// 再試行する場合は、試行ごとにタイムアウトを設定する
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithHTTPClient はリクエストを送信するクライアントを指定する。NewClientの引数よりも優先する
+func WithHTTPClient(httpClient HTTPDoer) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
 }
 
 type Content struct {
 	ID string `json:"id"`
 }
 
-type PublishRequest struct {
-	Title   string `json:"title"`
-	Tags    string `json:"tags"`
-	QiitaID string `json:"qiitaId"`
-	Content string `json:"content"`
+type CreateResponse struct {
+	ID string `json:"id"`
 }
 
 type CheckExistsResponse struct {
@@ -36,41 +98,119 @@ type CheckExistsResponse struct {
 	Contents   []Content `json:"contents"`
 }
 
-func NewClient(serviceID, apiKey, endpoint string, httpClient HTTPDoer) *Client {
-	return &Client{
+type FindResponse struct {
+	TotalCount int                      `json:"totalCount"`
+	Contents   []map[string]interface{} `json:"contents"`
+}
+
+// NewClient はエンドポイントのクライアントを作成する。httpClientがnilの場合はhttp.DefaultClientを使用する
+func NewClient(serviceID, apiKey, endpoint string, httpClient HTTPDoer, opts ...Option) *Client {
+	c := &Client{
 		apiKey:     apiKey,
 		httpClient: httpClient,
-		baseURL:    fmt.Sprintf("https://%s.microcms.io/api/v1/%s", serviceID, endpoint),
+		apiBase:    fmt.Sprintf("https://%s.microcms.io/api", serviceID),
+		apiVersion: defaultAPIVersion,
+		endpoint:   endpoint,
+		mapping:    DefaultFieldMapping(),
+		sleep:      sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
 	}
+	c.baseURL = fmt.Sprintf("%s/%s/%s", c.apiBase, c.apiVersion, c.endpoint)
+	return c
 }
 
-func (c *Client) Create(ctx context.Context, title, tags, qiitaID, content string) error {
-	req := PublishRequest{
-		Title:   title,
-		Tags:    tags,
-		QiitaID: qiitaID,
-		Content: content,
+// ContentStatus はコンテンツの公開状態
+type ContentStatus string
+
+const (
+	StatusPublish ContentStatus = "PUBLISH"
+	StatusDraft   ContentStatus = "DRAFT"
+	// 公開中のコンテンツに下書きがある状態（マネジメントAPIのみが返す）
+	StatusPublishAndDraft ContentStatus = "PUBLISH_AND_DRAFT"
+	// 公開終了（マネジメントAPIのみが返す）
+	StatusClosed ContentStatus = "CLOSED"
+)
+
+// statusOf はコンテンツの公開状態を返す
+// コンテンツAPIは公開状態を返さないため、公開されていないコンテンツにはない publishedAt の有無で判定する
+// 公開されたことがあるコンテンツは下書きに戻しても publishedAt が残る場合があるため、
+// 公開中かどうかを確かめる必要がある場合はマネジメントAPI（ContentManager）を使う
+func statusOf(content map[string]interface{}) ContentStatus {
+	if publishedAt, _ := content["publishedAt"].(string); publishedAt != "" {
+		return StatusPublish
 	}
+	return StatusDraft
+}
+
+// WriteOption はCreate・Updateの動作を指定する
+type WriteOption func(*writeOptions)
 
-	return c.sendRequest(ctx, http.MethodPost, c.baseURL, req, nil)
+type writeOptions struct {
+	draft bool
 }
 
-func (c *Client) Update(ctx context.Context, id, title, tags, qiitaId, content string) error {
-	apiUrl := fmt.Sprintf("%s/%s", c.baseURL, id)
-	req := PublishRequest{
-		Title:   title,
-		Tags:    tags,
-		QiitaID: qiitaId,
-		Content: content,
+// AsDraft はコンテンツを下書きとして保存する
+// 公開中のコンテンツに指定した場合は、公開中の内容はそのままで下書きが追加される（PUBLISH_AND_DRAFT）
+// 公開を止める場合はContentManager.SetStatusを使う
+func AsDraft() WriteOption {
+	return func(o *writeOptions) {
+		o.draft = true
 	}
+}
+
+func (c *Client) Create(ctx context.Context, fields Fields, opts ...WriteOption) (string, error) {
+	apiUrl := withStatus(c.baseURL, opts)
+	req := c.mapping.Payload(fields)
+
+	var response CreateResponse
+	if err := c.sendRequest(ctx, http.MethodPost, apiUrl, req, &response); err != nil {
+		return "", err
+	}
+	return response.ID, nil
+}
+
+func (c *Client) Update(ctx context.Context, id string, fields Fields, opts ...WriteOption) error {
+	apiUrl := withStatus(fmt.Sprintf("%s/%s", c.baseURL, id), opts)
+	req := c.mapping.Payload(fields)
 
 	return c.sendRequest(ctx, http.MethodPatch, apiUrl, req, nil)
 }
 
+func withStatus(apiUrl string, opts []WriteOption) string {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.draft {
+		return apiUrl + "?status=draft"
+	}
+	return apiUrl
+}
+
+// Get はコンテンツを取得し、MicroCMSのフィールドIDをキーとした値と公開状態を返す
+func (c *Client) Get(ctx context.Context, id string) (map[string]interface{}, ContentStatus, error) {
+	apiUrl := fmt.Sprintf("%s/%s", c.baseURL, id)
+
+	var response map[string]interface{}
+	if err := c.sendRequest(ctx, http.MethodGet, apiUrl, nil, &response); err != nil {
+		return nil, "", err
+	}
+	return response, statusOf(response), nil
+}
+
+func (c *Client) Delete(ctx context.Context, id string) error {
+	apiUrl := fmt.Sprintf("%s/%s", c.baseURL, id)
+
+	return c.sendRequest(ctx, http.MethodDelete, apiUrl, nil, nil)
+}
+
 func (c *Client) CheckExists(ctx context.Context, qiitaID string) (bool, string, error) {
-	rawFilter := fmt.Sprintf("qiitaId[equals]%s", qiitaID)
-	encodedFilter := url.QueryEscape(rawFilter)
-	apiUrl := fmt.Sprintf("%s?filters=%s", c.baseURL, encodedFilter)
+	apiUrl := c.filterURL(c.mapping[AttrQiitaID], qiitaID)
 
 	var response CheckExistsResponse
 	if err := c.sendRequest(ctx, http.MethodGet, apiUrl, nil, &response); err != nil {
@@ -84,14 +224,97 @@ func (c *Client) CheckExists(ctx context.Context, qiitaID string) (bool, string,
 	return false, "", nil
 }
 
+// Find はqiitaIDに一致するコンテンツを探し、コンテンツIDとフィールドIDをキーとした値、公開状態を返す
+// 見つからない場合はコンテンツIDが空になる
+func (c *Client) Find(ctx context.Context, qiitaID string) (string, map[string]interface{}, ContentStatus, error) {
+	id, content, err := c.findBy(ctx, c.mapping[AttrQiitaID], qiitaID)
+	if err != nil || id == "" {
+		return id, content, "", err
+	}
+	return id, content, statusOf(content), nil
+}
+
+// findBy はフィールドの値が一致する最初のコンテンツを探す
+func (c *Client) findBy(ctx context.Context, fieldID, value string) (string, map[string]interface{}, error) {
+	apiUrl := c.filterURL(fieldID, value)
+
+	var response FindResponse
+	if err := c.sendRequest(ctx, http.MethodGet, apiUrl, nil, &response); err != nil {
+		return "", nil, err
+	}
+
+	if response.TotalCount > 0 && len(response.Contents) > 0 {
+		content := response.Contents[0]
+		id, _ := content["id"].(string)
+		return id, content, nil
+	}
+
+	return "", nil, nil
+}
+
+func (c *Client) filterURL(fieldID, value string) string {
+	rawFilter := fmt.Sprintf("%s[equals]%s", fieldID, value)
+	encodedFilter := url.QueryEscape(rawFilter)
+	return fmt.Sprintf("%s?filters=%s", c.baseURL, encodedFilter)
+}
+
 func (c *Client) sendRequest(ctx context.Context, method, url string, requestBody, responseBody interface{}) error {
-	var body io.Reader
+	var jsonData []byte
 	if requestBody != nil {
-		jsonData, err := json.Marshal(requestBody)
+		var err error
+		jsonData, err = json.Marshal(requestBody)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
+	}
+
+	return c.send(ctx, method, url, jsonData, "application/json", responseBody)
+}
+
+// send はリクエストを送信し、429や5xxの場合は再試行する
+func (c *Client) send(ctx context.Context, method, url string, data []byte, contentType string, responseBody interface{}) error {
+	for attempt := 1; ; attempt++ {
+		err := c.doRequest(ctx, method, url, data, contentType, responseBody)
+		if err == nil {
+			return nil
+		}
+
+		statusCode, retryAfter := 0, time.Duration(0)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			statusCode, retryAfter = apiErr.StatusCode, apiErr.retryAfter
+		} else if !errors.Is(err, errTransport) {
+			// リクエストの作成やレスポンスのデコードの失敗は再試行しても解決しない
+			return err
+		}
+
+		if attempt >= c.retryPolicy.MaxAttempts || ctx.Err() != nil || !shouldRetry(method, statusCode) {
+			return err
+		}
+
+		if sleepErr := c.sleep(ctx, c.retryPolicy.delay(attempt, retryAfter)); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// errTransport は通信エラーを表す
+var errTransport = errors.New("request failed")
+
+func (c *Client) doRequest(ctx context.Context, method, url string, data []byte, contentType string, responseBody interface{}) error {
+	if err := c.rateLimiter.Wait(ctx, method); err != nil {
+		return err
+	}
+
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
@@ -100,19 +323,21 @@ func (c *Client) sendRequest(ctx context.Context, method, url string, requestBod
 	}
 
 	req.Header.Set("X-MICROCMS-API-KEY", c.apiKey)
-	if requestBody != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("%w: %w", errTransport, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("request failed with status code %d: %s", resp.StatusCode, string(bodyBytes))
+		return newAPIError(req, resp)
 	}
 
 	if responseBody != nil {
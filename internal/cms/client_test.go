@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -71,13 +72,13 @@ func TestClient_Create(t *testing.T) {
 
 					// リクエストボディの検証
 					body, _ := io.ReadAll(req.Body)
-					var requestBody PublishRequest
+					var requestBody map[string]interface{}
 					if err := json.Unmarshal(body, &requestBody); err != nil {
 						t.Errorf("Failed to unmarshal request body: %v", err)
 					}
 
-					if requestBody.Title != "Test Title" {
-						t.Errorf("Expected title 'Test Title', got %s", requestBody.Title)
+					if requestBody["title"] != "Test Title" {
+						t.Errorf("Expected title 'Test Title', got %v", requestBody["title"])
 					}
 
 					// レスポンスの作成
@@ -89,11 +90,20 @@ func TestClient_Create(t *testing.T) {
 			}
 
 			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
-			err := client.Create(context.Background(), "Test Title", "tag1,tag2", "qiita-123", "Test Content")
+			id, err := client.Create(context.Background(), Fields{
+				AttrTitle:   "Test Title",
+				AttrTags:    "tag1,tag2",
+				AttrQiitaID: "qiita-123",
+				AttrContent: "Test Content",
+			})
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
 			}
+
+			if !tt.wantErr && id != "test-id" {
+				t.Errorf("Create() id = %v, want test-id", id)
+			}
 		})
 	}
 }
@@ -143,7 +153,12 @@ func TestClient_Update(t *testing.T) {
 			}
 
 			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
-			err := client.Update(context.Background(), "test-id", "Updated Title", "tag1,tag2", "qiita-123", "Updated Content")
+			err := client.Update(context.Background(), "test-id", Fields{
+				AttrTitle:   "Updated Title",
+				AttrTags:    "tag1,tag2",
+				AttrQiitaID: "qiita-123",
+				AttrContent: "Updated Content",
+			})
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
@@ -152,6 +167,323 @@ func TestClient_Update(t *testing.T) {
 	}
 }
 
+func TestClient_AsDraft(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		expectedURL string
+	}{
+		{
+			name:        "create as draft",
+			method:      http.MethodPost,
+			expectedURL: "https://service-id.microcms.io/api/v1/endpoint?status=draft",
+		},
+		{
+			name:        "update as draft",
+			method:      http.MethodPatch,
+			expectedURL: "https://service-id.microcms.io/api/v1/endpoint/test-id?status=draft",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					// リクエストURLの検証
+					if req.URL.String() != tt.expectedURL {
+						t.Errorf("Expected URL %s, got %s", tt.expectedURL, req.URL.String())
+					}
+
+					// HTTPメソッドの検証
+					if req.Method != tt.method {
+						t.Errorf("Expected method %s, got %s", tt.method, req.Method)
+					}
+
+					// レスポンスの作成
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"id": "test-id"}`)),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+			fields := Fields{AttrTitle: "Test Title", AttrQiitaID: "qiita-123"}
+
+			var err error
+			if tt.method == http.MethodPost {
+				_, err = client.Create(context.Background(), fields, AsDraft())
+			} else {
+				err = client.Update(context.Background(), "test-id", fields, AsDraft())
+			}
+
+			if err != nil {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_Options(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantURL       string
+		wantUserAgent string
+	}{
+		{
+			name:    "default",
+			wantURL: "https://service-id.microcms.io/api/v1/endpoint/content-id",
+		},
+		{
+			name:    "base URL",
+			opts:    []Option{WithBaseURL("http://localhost:8080/api/")},
+			wantURL: "http://localhost:8080/api/v1/endpoint/content-id",
+		},
+		{
+			name:    "API version",
+			opts:    []Option{WithAPIVersion("v2")},
+			wantURL: "https://service-id.microcms.io/api/v2/endpoint/content-id",
+		},
+		{
+			name:          "user agent",
+			opts:          []Option{WithUserAgent("microcms-publish/test")},
+			wantURL:       "https://service-id.microcms.io/api/v1/endpoint/content-id",
+			wantUserAgent: "microcms-publish/test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// NewClientの引数よりもWithHTTPClientで指定したクライアントを使用する
+			unused := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					t.Fatal("Unexpected request to the client passed to NewClient")
+					return nil, nil
+				},
+			}
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					if req.URL.String() != tt.wantURL {
+						t.Errorf("URL = %s, want %s", req.URL, tt.wantURL)
+					}
+					if tt.wantUserAgent != "" && req.Header.Get("User-Agent") != tt.wantUserAgent {
+						t.Errorf("User-Agent = %s, want %s", req.Header.Get("User-Agent"), tt.wantUserAgent)
+					}
+					return &http.Response{
+						StatusCode: http.StatusAccepted,
+						Body:       io.NopCloser(strings.NewReader("")),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", unused, append(tt.opts, WithHTTPClient(mockClient))...)
+			if err := client.Delete(context.Background(), "content-id"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_WithFieldMapping(t *testing.T) {
+	mapping := FieldMapping{
+		AttrTitle:   "title",
+		AttrQiitaID: "sourceId",
+		AttrContent: "body",
+		"slug":      "slug",
+	}
+
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			switch req.Method {
+			case http.MethodGet:
+				// 対応表のフィールドIDで絞り込む
+				if req.URL.Query().Get("filters") != "sourceId[equals]qiita-123" {
+					t.Errorf("Unexpected filters %s", req.URL.Query().Get("filters"))
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"totalCount": 0, "contents": []}`)),
+				}, nil
+			case http.MethodPost:
+				// 対応表に含まれるフィールドだけを送る
+				body, _ := io.ReadAll(req.Body)
+				var requestBody map[string]interface{}
+				if err := json.Unmarshal(body, &requestBody); err != nil {
+					t.Errorf("Failed to unmarshal request body: %v", err)
+				}
+				expected := map[string]interface{}{
+					"title":    "Test Title",
+					"sourceId": "qiita-123",
+					"body":     "Test Content",
+					"slug":     "test-slug",
+				}
+				if !reflect.DeepEqual(expected, requestBody) {
+					t.Errorf("Expected body %v, got %v", expected, requestBody)
+				}
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       io.NopCloser(strings.NewReader(`{"id": "test-id"}`)),
+				}, nil
+			}
+			t.Fatalf("Unexpected method %s", req.Method)
+			return nil, nil
+		},
+	}
+
+	client := NewClient("service-id", "test-api-key", "endpoint", mockClient, WithFieldMapping(mapping))
+
+	if _, _, err := client.CheckExists(context.Background(), "qiita-123"); err != nil {
+		t.Fatalf("CheckExists() error = %v", err)
+	}
+
+	_, err := client.Create(context.Background(), Fields{
+		AttrTitle:   "Test Title",
+		AttrTags:    "tag1,tag2",
+		AttrQiitaID: "qiita-123",
+		AttrContent: "Test Content",
+		"slug":      "test-slug",
+		"private":   false,
+	})
+	if err != nil {
+		t.Errorf("Create() error = %v", err)
+	}
+}
+
+func TestClient_Get(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respBody   string
+		want       map[string]interface{}
+		wantStatus ContentStatus
+		wantErr    bool
+	}{
+		{
+			name:       "successful get",
+			statusCode: http.StatusOK,
+			respBody:   `{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123", "publishedAt": "2025-03-23T11:50:41.000Z"}`,
+			want:       map[string]interface{}{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123", "publishedAt": "2025-03-23T11:50:41.000Z"},
+			wantStatus: StatusPublish,
+			wantErr:    false,
+		},
+		{
+			name:       "draft",
+			statusCode: http.StatusOK,
+			respBody:   `{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123"}`,
+			want:       map[string]interface{}{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123"},
+			wantStatus: StatusDraft,
+			wantErr:    false,
+		},
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			respBody:   `{"message": "Not found"}`,
+			want:       nil,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					// リクエストURLの検証
+					expectedURL := "https://service-id.microcms.io/api/v1/endpoint/test-id"
+					if req.URL.String() != expectedURL {
+						t.Errorf("Expected URL %s, got %s", expectedURL, req.URL.String())
+					}
+
+					// HTTPメソッドの検証
+					if req.Method != http.MethodGet {
+						t.Errorf("Expected method GET, got %s", req.Method)
+					}
+
+					// レスポンスの作成
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(tt.respBody)),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+			content, status, err := client.Get(context.Background(), "test-id")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(tt.want, content) {
+				t.Errorf("Get() = %v, want %v", content, tt.want)
+			}
+
+			if status != tt.wantStatus {
+				t.Errorf("Get() status = %v, want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestClient_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respBody   string
+		wantErr    bool
+	}{
+		{
+			name:       "successful deletion",
+			statusCode: http.StatusAccepted,
+			respBody:   ``,
+			wantErr:    false,
+		},
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			respBody:   `{"message": "Not found"}`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					// リクエストURLの検証
+					expectedURL := "https://service-id.microcms.io/api/v1/endpoint/test-id"
+					if req.URL.String() != expectedURL {
+						t.Errorf("Expected URL %s, got %s", expectedURL, req.URL.String())
+					}
+
+					// HTTPメソッドの検証
+					if req.Method != http.MethodDelete {
+						t.Errorf("Expected method DELETE, got %s", req.Method)
+					}
+
+					// DELETEはボディを送らない
+					if req.Body != nil {
+						t.Errorf("Expected empty request body")
+					}
+
+					// レスポンスの作成
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(tt.respBody)),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+			err := client.Delete(context.Background(), "test-id")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestClient_CheckExists(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -228,6 +560,97 @@ func TestClient_CheckExists(t *testing.T) {
 	}
 }
 
+func TestClient_Find(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		respBody    string
+		wantID      string
+		wantContent map[string]interface{}
+		wantStatus  ContentStatus
+		wantErr     bool
+	}{
+		{
+			name:        "content exists",
+			statusCode:  http.StatusOK,
+			respBody:    `{"totalCount": 1, "contents": [{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123", "publishedAt": "2025-03-23T11:50:41.000Z"}]}`,
+			wantID:      "test-id",
+			wantContent: map[string]interface{}{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123", "publishedAt": "2025-03-23T11:50:41.000Z"},
+			wantStatus:  StatusPublish,
+			wantErr:     false,
+		},
+		{
+			name:        "draft content exists",
+			statusCode:  http.StatusOK,
+			respBody:    `{"totalCount": 1, "contents": [{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123"}]}`,
+			wantID:      "test-id",
+			wantContent: map[string]interface{}{"id": "test-id", "title": "Test Title", "qiitaId": "qiita-123"},
+			wantStatus:  StatusDraft,
+			wantErr:     false,
+		},
+		{
+			name:        "content does not exist",
+			statusCode:  http.StatusOK,
+			respBody:    `{"totalCount": 0, "contents": []}`,
+			wantID:      "",
+			wantContent: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "API error",
+			statusCode:  http.StatusInternalServerError,
+			respBody:    `{"message": "Internal server error"}`,
+			wantID:      "",
+			wantContent: nil,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					// クエリパラメータの検証
+					if req.URL.Query().Get("filters") != "qiitaId[equals]qiita-123" {
+						t.Errorf("Unexpected filters %s", req.URL.Query().Get("filters"))
+					}
+
+					// HTTPメソッドの検証
+					if req.Method != http.MethodGet {
+						t.Errorf("Expected method GET, got %s", req.Method)
+					}
+
+					// レスポンスの作成
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(tt.respBody)),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+			id, content, status, err := client.Find(context.Background(), "qiita-123")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Find() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if id != tt.wantID {
+				t.Errorf("Find() id = %v, want %v", id, tt.wantID)
+			}
+
+			if !reflect.DeepEqual(tt.wantContent, content) {
+				t.Errorf("Find() content = %v, want %v", content, tt.wantContent)
+			}
+
+			if status != tt.wantStatus {
+				t.Errorf("Find() status = %v, want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestSendRequest(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,134 @@
+package cmstest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filters パラメータの1つの条件
+//
+//	qiitaId[equals]abc
+//	tags[contains]Go
+//	description[exists]
+var conditionPattern = regexp.MustCompile(`^([^\[\]]+)\[(equals|not_equals|contains|not_contains|exists|not_exists|less_than|greater_than)\](.*)$`)
+
+// filter は `[or]` で区切られた、`[and]` で連結した条件の組
+type filter [][]condition
+
+type condition struct {
+	field    string
+	operator string
+	value    string
+}
+
+func parseFilters(value string) (filter, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	f := make(filter, 0)
+	for _, group := range strings.Split(value, "[or]") {
+		conditions := make([]condition, 0)
+		for _, expr := range strings.Split(group, "[and]") {
+			m := conditionPattern.FindStringSubmatch(expr)
+			if m == nil {
+				return nil, fmt.Errorf("Invalid filters parameter: %s", expr)
+			}
+			conditions = append(conditions, condition{field: m[1], operator: m[2], value: m[3]})
+		}
+		f = append(f, conditions)
+	}
+	return f, nil
+}
+
+// match はいずれかの組のすべての条件を満たす場合にtrueを返す。条件がない場合は常にtrue
+func (f filter) match(content map[string]interface{}) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, conditions := range f {
+		matched := true
+		for _, c := range conditions {
+			if !c.match(content) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c condition) match(content map[string]interface{}) bool {
+	value, exists := content[c.field]
+	if exists && value == nil {
+		exists = false
+	}
+
+	switch c.operator {
+	case "exists":
+		return exists
+	case "not_exists":
+		return !exists
+	case "equals":
+		return exists && equals(value, c.value)
+	case "not_equals":
+		return !exists || !equals(value, c.value)
+	case "contains":
+		return exists && strings.Contains(valueString(value), c.value)
+	case "not_contains":
+		return !exists || !strings.Contains(valueString(value), c.value)
+	case "less_than":
+		return exists && compare(value, c.value) < 0
+	case "greater_than":
+		return exists && compare(value, c.value) > 0
+	}
+	return false
+}
+
+// equals は値を比較する。参照フィールドはコンテンツID、複数の値を持つフィールドはいずれかの値と比較する
+func equals(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if equals(item, want) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		return valueString(v["id"]) == want
+	}
+	return valueString(value) == want
+}
+
+func compare(value interface{}, want string) int {
+	if n, ok := value.(float64); ok {
+		if w, err := strconv.ParseFloat(want, 64); err == nil {
+			switch {
+			case n < w:
+				return -1
+			case n > w:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(valueString(value), want)
+}
+
+func valueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,462 @@
+// Package cmstest はテスト用に、MicroCMSのコンテンツAPIをローカルで再現するサーバーを提供する
+//
+// 一覧（limit・offset・fields・filters・orders）・取得・作成・更新・削除と
+// APIキーの検証に対応し、エラーはMicroCMSと同じ `{"message": "..."}` の形式で返す
+//
+// マネジメントAPIのコンテンツの公開状態の取得・変更（`/api/v1/contents/{endpoint}/{id}`）にも同じURLで対応する
+package cmstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MicroCMSの一覧取得の既定の件数と最大件数
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// Content の公開状態
+const (
+	StatusPublish = "PUBLISH"
+	StatusDraft   = "DRAFT"
+	// 公開中のコンテンツに下書きがある状態
+	StatusPublishAndDraft = "PUBLISH_AND_DRAFT"
+)
+
+// Server はテスト用のMicroCMSのAPIサーバー
+type Server struct {
+	*httptest.Server
+
+	apiKey string
+
+	mu        sync.Mutex
+	endpoints map[string]*endpoint
+	nextID    int
+	requests  []string
+	failures  []failure
+}
+
+// endpoint は1つのAPIのコンテンツを作成順に保持する
+type endpoint struct {
+	contents []*content
+}
+
+type content struct {
+	id     string
+	status string
+	// 公開中の内容（下書きのコンテンツは下書きの内容）
+	fields map[string]interface{}
+	// 公開中のコンテンツに保存された下書きの内容（StatusPublishAndDraftの場合のみ）
+	draft map[string]interface{}
+}
+
+// failure は次のリクエストに返すエラー
+type failure struct {
+	statusCode int
+	message    string
+}
+
+// NewServer はapiKeyのみを受け付けるサーバーを起動する
+// endpointsに指定したAPI以外へのリクエストは404になる
+func NewServer(apiKey string, endpoints ...string) *Server {
+	s := &Server{
+		apiKey:    apiKey,
+		endpoints: make(map[string]*endpoint, len(endpoints)),
+	}
+	for _, name := range endpoints {
+		s.endpoints[name] = &endpoint{}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// BaseURL はcms.WithBaseURLに指定するURLを返す
+func (s *Server) BaseURL() string {
+	return s.URL + "/api"
+}
+
+// Put はコンテンツを公開状態で登録し、コンテンツIDを返す
+func (s *Server) Put(endpointName string, fields map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.create(s.endpoints[endpointName], fields, StatusPublish)
+	return c.id
+}
+
+// Contents は登録されているコンテンツを作成順に返す
+func (s *Server) Contents(endpointName string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents := make([]map[string]interface{}, 0)
+	for _, c := range s.endpoints[endpointName].contents {
+		contents = append(contents, c.response())
+	}
+	return contents
+}
+
+// Status はコンテンツの公開状態（StatusPublish・StatusDraft・StatusPublishAndDraft）を返す。存在しない場合は空文字を返す
+func (s *Server) Status(endpointName, id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c := s.endpoints[endpointName].find(id); c != nil {
+		return c.status
+	}
+	return ""
+}
+
+// Requests は受け付けたリクエストを `METHOD /path` の形式で順に返す
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// Fail は次のリクエストに、指定したステータスコードのエラーを返す
+// 複数回呼び出した場合は、呼び出した順に後続のリクエストに返す
+func (s *Server) Fail(statusCode int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure{statusCode: statusCode, message: message})
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+
+	if r.Header.Get("X-MICROCMS-API-KEY") != s.apiKey {
+		writeError(w, http.StatusUnauthorized, "X-MICROCMS-API-KEY header is invalid.")
+		return
+	}
+
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+		writeError(w, f.statusCode, f.message)
+		return
+	}
+
+	// /api/v1/{endpoint} または /api/v1/{endpoint}/{id}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/"), "/")
+	if parts[0] == "contents" && len(parts) > 2 {
+		s.manage(w, r, parts[1:])
+		return
+	}
+	e, ok := s.endpoints[parts[0]]
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/") || !ok || len(parts) > 2 {
+		writeError(w, http.StatusNotFound, "API not found.")
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			s.list(w, r, e)
+		case http.MethodPost:
+			s.post(w, r, e)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		}
+		return
+	}
+
+	c := e.find(parts[1])
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Content is not found.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, c.response())
+	case http.MethodPatch:
+		s.patch(w, r, c)
+	case http.MethodDelete:
+		e.delete(c.id)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, e *endpoint) {
+	query := r.URL.Query()
+
+	limit, err := intParam(query.Get("limit"), defaultLimit)
+	if err != nil || limit < 0 || limit > maxLimit {
+		writeError(w, http.StatusBadRequest, "Invalid limit parameter.")
+		return
+	}
+	offset, err := intParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "Invalid offset parameter.")
+		return
+	}
+	filter, err := parseFilters(query.Get("filters"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matched := make([]map[string]interface{}, 0)
+	for _, c := range e.contents {
+		if response := c.response(); filter.match(response) {
+			matched = append(matched, response)
+		}
+	}
+	sortContents(matched, query.Get("orders"))
+
+	page := make([]map[string]interface{}, 0)
+	if offset < len(matched) {
+		page = matched[offset:min(offset+limit, len(matched))]
+	}
+	if fields := query.Get("fields"); fields != "" {
+		page = selectFields(page, strings.Split(fields, ","))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"contents":   page,
+		"totalCount": len(matched),
+		"offset":     offset,
+		"limit":      limit,
+	})
+}
+
+func (s *Server) post(w http.ResponseWriter, r *http.Request, e *endpoint) {
+	fields, ok := decodeBody(w, r)
+	if !ok {
+		return
+	}
+
+	c := s.create(e, fields, requestStatus(r))
+	writeJSON(w, http.StatusCreated, map[string]string{"id": c.id})
+}
+
+// patch はコンテンツを更新する
+// MicroCMSと同じく、公開中のコンテンツを `?status=draft` で更新した場合は公開中の内容を変えずに下書きを保存する
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, c *content) {
+	fields, ok := decodeBody(w, r)
+	if !ok {
+		return
+	}
+
+	timestamp := now()
+	if requestStatus(r) == StatusDraft && c.status != StatusDraft {
+		if c.draft == nil {
+			c.draft = c.response()
+			delete(c.draft, "id")
+		}
+		for key, value := range fields {
+			c.draft[key] = value
+		}
+		c.draft["updatedAt"] = timestamp
+		c.status = StatusPublishAndDraft
+		writeJSON(w, http.StatusOK, map[string]string{"id": c.id})
+		return
+	}
+
+	c.applyDraft()
+	for key, value := range fields {
+		c.fields[key] = value
+	}
+	c.fields["updatedAt"] = timestamp
+	c.setStatus(requestStatus(r), timestamp)
+	writeJSON(w, http.StatusOK, map[string]string{"id": c.id})
+}
+
+// manage はマネジメントAPIの `/api/v1/contents/{endpoint}/{id}`（公開状態の取得）と
+// `/api/v1/contents/{endpoint}/{id}/status`（公開状態の変更）に応答する
+func (s *Server) manage(w http.ResponseWriter, r *http.Request, parts []string) {
+	e, ok := s.endpoints[parts[0]]
+	if !ok || len(parts) > 3 || (len(parts) == 3 && parts[2] != "status") {
+		writeError(w, http.StatusNotFound, "API not found.")
+		return
+	}
+	c := e.find(parts[1])
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Content is not found.")
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		response := c.response()
+		response["status"] = []string{c.status}
+		writeJSON(w, http.StatusOK, response)
+	case len(parts) == 3 && r.Method == http.MethodPatch:
+		var body struct {
+			Status []string `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Status) != 1 ||
+			(body.Status[0] != StatusPublish && body.Status[0] != StatusDraft) {
+			writeError(w, http.StatusBadRequest, "Invalid status.")
+			return
+		}
+		c.applyDraft()
+		c.setStatus(body.Status[0], now())
+		writeJSON(w, http.StatusOK, map[string]string{"id": c.id})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
+
+func (s *Server) create(e *endpoint, fields map[string]interface{}, status string) *content {
+	s.nextID++
+	c := &content{
+		id:     fmt.Sprintf("content%04d", s.nextID),
+		status: status,
+		fields: make(map[string]interface{}, len(fields)+3),
+	}
+	timestamp := now()
+	c.fields["createdAt"] = timestamp
+	c.fields["updatedAt"] = timestamp
+	for key, value := range fields {
+		c.fields[key] = value
+	}
+	c.setStatus(status, timestamp)
+	e.contents = append(e.contents, c)
+	return c
+}
+
+// applyDraft は保存されている下書きの内容を反映する
+func (c *content) applyDraft() {
+	if c.draft != nil {
+		c.fields = c.draft
+		c.draft = nil
+	}
+}
+
+// setStatus は公開状態を変更する
+// 公開されていないコンテンツはpublishedAtを返さない
+func (c *content) setStatus(status, timestamp string) {
+	c.status = status
+	if status == StatusDraft {
+		delete(c.fields, "publishedAt")
+		return
+	}
+	if _, ok := c.fields["publishedAt"]; !ok {
+		c.fields["publishedAt"] = timestamp
+	}
+}
+
+func (e *endpoint) find(id string) *content {
+	if e == nil {
+		return nil
+	}
+	for _, c := range e.contents {
+		if c.id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (e *endpoint) delete(id string) {
+	for i, c := range e.contents {
+		if c.id == id {
+			e.contents = append(e.contents[:i], e.contents[i+1:]...)
+			return
+		}
+	}
+}
+
+// response はAPIが返すコンテンツ（システムフィールドを含む）を返す
+func (c *content) response() map[string]interface{} {
+	response := make(map[string]interface{}, len(c.fields)+1)
+	for key, value := range c.fields {
+		response[key] = value
+	}
+	response["id"] = c.id
+	return response
+}
+
+// requestStatus は `?status=draft` が指定されたリクエストを下書きとして扱う
+func requestStatus(r *http.Request) string {
+	if r.URL.Query().Get("status") == "draft" {
+		return StatusDraft
+	}
+	return StatusPublish
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body.")
+		return nil, false
+	}
+	if _, ok := fields["id"]; ok {
+		writeError(w, http.StatusBadRequest, "id cannot be specified in the request body.")
+		return nil, false
+	}
+	return fields, true
+}
+
+func selectFields(contents []map[string]interface{}, fields []string) []map[string]interface{} {
+	selected := make([]map[string]interface{}, 0, len(contents))
+	for _, c := range contents {
+		s := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if value, ok := c[field]; ok {
+				s[field] = value
+			}
+		}
+		selected = append(selected, s)
+	}
+	return selected
+}
+
+// sortContents は `orders` に指定されたフィールドの順（先頭が `-` の場合は降順）に並べ替える
+func sortContents(contents []map[string]interface{}, orders string) {
+	if orders == "" {
+		return
+	}
+	keys := strings.Split(orders, ",")
+	sort.SliceStable(contents, func(i, j int) bool {
+		for _, key := range keys {
+			desc := strings.HasPrefix(key, "-")
+			field := strings.TrimPrefix(key, "-")
+			a, b := valueString(contents[i][field]), valueString(contents[j][field])
+			if a == b {
+				continue
+			}
+			return (a < b) != desc
+		}
+		return false
+	})
+}
+
+func intParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]string{"message": message})
+}
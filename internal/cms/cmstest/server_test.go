@@ -0,0 +1,215 @@
+package cmstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Kdaito/microcms-publish/internal/cms"
+)
+
+func newClient(s *Server, apiKey string) *cms.Client {
+	return cms.NewClient("service-id", apiKey, "items", http.DefaultClient,
+		cms.WithBaseURL(s.BaseURL()),
+		cms.WithRetryPolicy(cms.RetryPolicy{MaxAttempts: 1}),
+	)
+}
+
+func TestServer_List(t *testing.T) {
+	s := NewServer("test-api-key", "items")
+	defer s.Close()
+	s.Put("items", map[string]interface{}{"title": "b", "qiitaId": "qiita-1", "tags": "Go"})
+	s.Put("items", map[string]interface{}{"title": "a", "qiitaId": "qiita-2", "tags": "Go,Test"})
+	s.Put("items", map[string]interface{}{"title": "c", "tags": "Test"})
+
+	tests := []struct {
+		name string
+		opts cms.ListOptions
+		want []string
+	}{
+		{
+			name: "pagination",
+			opts: cms.ListOptions{Limit: 2, Offset: 1},
+			want: []string{"a", "c"},
+		},
+		{
+			name: "filters",
+			opts: cms.ListOptions{Filters: "tags[contains]Go[and]qiitaId[not_equals]qiita-1[or]qiitaId[not_exists]"},
+			want: []string{"a", "c"},
+		},
+		{
+			name: "orders",
+			opts: cms.ListOptions{Orders: []string{"-title"}},
+			want: []string{"c", "b", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := newClient(s, "test-api-key").List(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			titles := make([]string, 0)
+			for _, content := range response.Contents {
+				titles = append(titles, content["title"].(string))
+			}
+			if !reflect.DeepEqual(titles, tt.want) {
+				t.Errorf("List() = %v, want %v", titles, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Write(t *testing.T) {
+	s := NewServer("test-api-key", "items")
+	defer s.Close()
+	client := newClient(s, "test-api-key")
+	ctx := context.Background()
+
+	createdID, err := client.Create(ctx, cms.Fields{cms.AttrTitle: "title", cms.AttrQiitaID: "qiita-1"}, cms.AsDraft())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if status := s.Status("items", createdID); status != StatusDraft {
+		t.Errorf("Status() = %s, want %s", status, StatusDraft)
+	}
+
+	if err := client.Update(ctx, createdID, cms.Fields{cms.AttrTitle: "updated"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	id, existing, status, err := client.Find(ctx, "qiita-1")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	// 公開状態を指定せずに更新すると公開される
+	if id != createdID || existing["title"] != "updated" || status != cms.StatusPublish {
+		t.Errorf("Find() = %s, %v, %s", id, existing, status)
+	}
+
+	if err := client.Delete(ctx, createdID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if contents := s.Contents("items"); len(contents) != 0 {
+		t.Errorf("Contents() = %v, want empty", contents)
+	}
+
+	want := []string{"POST /api/v1/items", "PATCH /api/v1/items/" + createdID, "GET /api/v1/items", "DELETE /api/v1/items/" + createdID}
+	if requests := s.Requests(); !reflect.DeepEqual(requests, want) {
+		t.Errorf("Requests() = %v, want %v", requests, want)
+	}
+}
+
+func TestServer_Status(t *testing.T) {
+	s := NewServer("test-api-key", "items")
+	defer s.Close()
+	client := newClient(s, "test-api-key")
+	manager := cms.NewContentManager("service-id", "test-api-key", "items", http.DefaultClient,
+		cms.WithBaseURL(s.BaseURL()),
+		cms.WithRetryPolicy(cms.RetryPolicy{MaxAttempts: 1}),
+	)
+	ctx := context.Background()
+	id := s.Put("items", map[string]interface{}{"title": "title", "qiitaId": "qiita-1"})
+
+	// 公開中のコンテンツを下書きとして更新しても、公開中の内容は変わらない
+	if err := client.Update(ctx, id, cms.Fields{cms.AttrTitle: "draft"}, cms.AsDraft()); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	_, existing, status, err := client.Find(ctx, "qiita-1")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if existing["title"] != "title" || status != cms.StatusPublish {
+		t.Errorf("Find() = %v, %s", existing, status)
+	}
+	if status, err := manager.Status(ctx, id); err != nil || status != cms.StatusPublishAndDraft {
+		t.Errorf("ContentManager.Status() = %s, %v, want %s", status, err, cms.StatusPublishAndDraft)
+	}
+
+	// 下書きに戻すと、保存された下書きの内容になる
+	if err := manager.SetStatus(ctx, id, cms.StatusDraft); err != nil {
+		t.Fatalf("SetStatus() error = %v", err)
+	}
+	_, existing, status, err = client.Find(ctx, "qiita-1")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if existing["title"] != "draft" || status != cms.StatusDraft {
+		t.Errorf("Find() = %v, %s", existing, status)
+	}
+	if status := s.Status("items", id); status != StatusDraft {
+		t.Errorf("Status() = %s, want %s", status, StatusDraft)
+	}
+
+	if err := manager.SetStatus(ctx, id, cms.StatusPublish); err != nil {
+		t.Fatalf("SetStatus() error = %v", err)
+	}
+	if status := s.Status("items", id); status != StatusPublish {
+		t.Errorf("Status() = %s, want %s", status, StatusPublish)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	s := NewServer("test-api-key", "items")
+	defer s.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		apiKey      string
+		setup       func()
+		call        func(c *cms.Client) error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "invalid API key",
+			apiKey:      "invalid",
+			call:        func(c *cms.Client) error { _, err := c.List(ctx, cms.ListOptions{}); return err },
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "X-MICROCMS-API-KEY header is invalid.",
+		},
+		{
+			name:        "content not found",
+			apiKey:      "test-api-key",
+			call:        func(c *cms.Client) error { return c.Delete(ctx, "unknown") },
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "Content is not found.",
+		},
+		{
+			name:        "limit exceeded",
+			apiKey:      "test-api-key",
+			call:        func(c *cms.Client) error { _, err := c.List(ctx, cms.ListOptions{Limit: 101}); return err },
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Invalid limit parameter.",
+		},
+		{
+			name:        "injected failure",
+			apiKey:      "test-api-key",
+			setup:       func() { s.Fail(http.StatusInternalServerError, "Internal server error") },
+			call:        func(c *cms.Client) error { _, err := c.List(ctx, cms.ListOptions{}); return err },
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			err := tt.call(newClient(s, tt.apiKey))
+			var apiErr *cms.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *cms.APIError", err)
+			}
+			if apiErr.StatusCode != tt.wantStatus || apiErr.Message != tt.wantMessage {
+				t.Errorf("error = %d %s, want %d %s", apiErr.StatusCode, apiErr.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package cms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// APIError はMicroCMSが2xx以外のステータスコードを返したことを表す
+type APIError struct {
+	StatusCode int
+	// MicroCMSのエラーレスポンスのmessage（JSONでない場合はレスポンスボディ）
+	Message string
+	Method  string
+	URL     string
+
+	retryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: request failed with status code %d: %s", e.Method, e.URL, e.StatusCode, e.Message)
+}
+
+// IsAuthError はAPIキーが誤っている、または権限が不足していることを表す
+// 以降のリクエストもすべて失敗するため、呼び出し側は処理を中断すべき
+func (e *APIError) IsAuthError() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// IsRateLimited はMicroCMSのレート制限を超えたことを表す
+func (e *APIError) IsRateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsNotFound はコンテンツが存在しないことを表す
+func (e *APIError) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsAuthError はerrがAPIキーや権限の誤りによるエラーかを判定する
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.IsAuthError()
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func newAPIError(req *http.Request, resp *http.Response) *APIError {
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	message := string(bodyBytes)
+	var response errorResponse
+	if err := json.Unmarshal(bodyBytes, &response); err == nil && response.Message != "" {
+		message = response.Message
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
@@ -0,0 +1,113 @@
+package cms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		respBody        string
+		wantMessage     string
+		wantAuthError   bool
+		wantRateLimited bool
+		wantNotFound    bool
+	}{
+		{
+			name:          "unauthorized",
+			statusCode:    http.StatusUnauthorized,
+			respBody:      `{"message": "X-MICROCMS-API-KEY header is invalid."}`,
+			wantMessage:   "X-MICROCMS-API-KEY header is invalid.",
+			wantAuthError: true,
+		},
+		{
+			name:          "forbidden",
+			statusCode:    http.StatusForbidden,
+			respBody:      `{"message": "Forbidden"}`,
+			wantMessage:   "Forbidden",
+			wantAuthError: true,
+		},
+		{
+			name:        "schema mismatch",
+			statusCode:  http.StatusBadRequest,
+			respBody:    `{"message": "Field 'tags' is not found."}`,
+			wantMessage: "Field 'tags' is not found.",
+		},
+		{
+			name:            "rate limited",
+			statusCode:      http.StatusTooManyRequests,
+			respBody:        `{"message": "Too many requests"}`,
+			wantMessage:     "Too many requests",
+			wantRateLimited: true,
+		},
+		{
+			name:         "not found without json body",
+			statusCode:   http.StatusNotFound,
+			respBody:     `Not Found`,
+			wantMessage:  "Not Found",
+			wantNotFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(tt.respBody)),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+			_, err := client.Create(context.Background(), Fields{AttrTitle: "Test Title"})
+
+			// 呼び出し側でラップされていても取り出せる
+			wrapped := fmt.Errorf("publishing qiita-123: %w", err)
+
+			var apiErr *APIError
+			if !errors.As(wrapped, &apiErr) {
+				t.Fatalf("Expected *APIError, got %T", err)
+			}
+
+			if apiErr.StatusCode != tt.statusCode {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.statusCode)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Method != http.MethodPost {
+				t.Errorf("Method = %s, want POST", apiErr.Method)
+			}
+			if apiErr.URL != "https://service-id.microcms.io/api/v1/endpoint" {
+				t.Errorf("URL = %s", apiErr.URL)
+			}
+			if apiErr.IsAuthError() != tt.wantAuthError || IsAuthError(wrapped) != tt.wantAuthError {
+				t.Errorf("IsAuthError() = %v, want %v", apiErr.IsAuthError(), tt.wantAuthError)
+			}
+			if apiErr.IsRateLimited() != tt.wantRateLimited {
+				t.Errorf("IsRateLimited() = %v, want %v", apiErr.IsRateLimited(), tt.wantRateLimited)
+			}
+			if apiErr.IsNotFound() != tt.wantNotFound {
+				t.Errorf("IsNotFound() = %v, want %v", apiErr.IsNotFound(), tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func TestIsAuthError_NotAPIError(t *testing.T) {
+	if IsAuthError(errors.New("request failed")) {
+		t.Error("Expected false for non API error")
+	}
+	if IsAuthError(nil) {
+		t.Error("Expected false for nil")
+	}
+}
@@ -0,0 +1,140 @@
+package cms
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// 記事の属性名
+const (
+	AttrTitle   = "title"
+	AttrTags    = "tags"
+	AttrQiitaID = "qiitaId"
+	AttrContent = "content"
+	// Qiitaでの更新日時
+	AttrUpdatedAt = "updatedAt"
+	// 最初に公開された日時（MicroCMSの公開日時にする場合は `publishedAt` に対応させる）
+	AttrPublishedAt = "publishedAt"
+	// 目次（設定ファイルの `toc.format` に応じて、JSONまたは繰り返しフィールドの形式で送信する）
+	AttrTOC = "toc"
+	// 概要（front matterのdescription、または本文の先頭の抜粋）
+	AttrExcerpt = "excerpt"
+)
+
+// MicroCMSの日時フィールドが返す形式
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+// FormatTime は日時をMicroCMSの日時フィールドと同じ形式の文字列にする
+// 同じ日時であれば既存のコンテンツと差分が出ないよう、UTCのミリ秒単位に揃える
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// Fields は記事の属性名をキーとした値
+type Fields map[string]interface{}
+
+// FieldMapping は記事の属性名をキー、MicroCMSのフィールドIDを値とする対応表
+type FieldMapping map[string]string
+
+func DefaultFieldMapping() FieldMapping {
+	return FieldMapping{
+		AttrTitle:   "title",
+		AttrTags:    "tags",
+		AttrQiitaID: "qiitaId",
+		AttrContent: "content",
+	}
+}
+
+// Payload は対応表に含まれる属性だけを、MicroCMSのフィールドIDをキーとして詰め替える
+func (m FieldMapping) Payload(fields Fields) map[string]interface{} {
+	payload := make(map[string]interface{}, len(m))
+	for attr, fieldID := range m {
+		if value, ok := fields[attr]; ok {
+			payload[fieldID] = value
+		}
+	}
+	return payload
+}
+
+// FieldDiff は既存のコンテンツと送信する値が異なるフィールド
+type FieldDiff struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// Diff は既存のコンテンツ（フィールドIDをキーとした値）と送信する値を比較し、
+// 異なるフィールドをフィールドIDの順に返す
+func (m FieldMapping) Diff(existing map[string]interface{}, fields Fields) []FieldDiff {
+	payload := m.Payload(fields)
+
+	fieldIDs := make([]string, 0, len(payload))
+	for fieldID := range payload {
+		fieldIDs = append(fieldIDs, fieldID)
+	}
+	sort.Strings(fieldIDs)
+
+	diffs := make([]FieldDiff, 0)
+	for _, fieldID := range fieldIDs {
+		newValue := normalize(payload[fieldID])
+		oldValue := referenceIDs(normalize(existing[fieldID]), newValue)
+		if !reflect.DeepEqual(oldValue, newValue) {
+			diffs = append(diffs, FieldDiff{Field: fieldID, Old: oldValue, New: newValue})
+		}
+	}
+	return diffs
+}
+
+// レスポンスのJSONと比較できるよう、JSONを経由して型を揃える
+func normalize(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// referenceIDs は参照フィールドの値をコンテンツIDに置き換える
+// 参照フィールドはIDを送信するが、取得時は参照先のコンテンツが展開されて返されるため、
+// 送信する値がIDの場合のみ、既存の値もIDにして比較する
+func referenceIDs(existing, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if id, ok := contentID(existing); ok {
+			return id
+		}
+	case []interface{}:
+		contents, ok := existing.([]interface{})
+		if !ok || len(v) == 0 {
+			return existing
+		}
+		if _, ok := v[0].(string); !ok {
+			return existing
+		}
+		ids := make([]interface{}, 0, len(contents))
+		for _, content := range contents {
+			id, ok := contentID(content)
+			if !ok {
+				return existing
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return existing
+}
+
+func contentID(value interface{}) (string, bool) {
+	content, ok := value.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	id, ok := content["id"].(string)
+	return id, ok
+}
@@ -0,0 +1,159 @@
+package cms
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFieldMapping_Payload(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  FieldMapping
+		fields   Fields
+		expected map[string]interface{}
+	}{
+		{
+			name:    "default mapping",
+			mapping: DefaultFieldMapping(),
+			fields: Fields{
+				AttrTitle:   "Title",
+				AttrTags:    "tag1,tag2",
+				AttrQiitaID: "qiita-123",
+				AttrContent: "<p>content</p>",
+			},
+			expected: map[string]interface{}{
+				"title":   "Title",
+				"tags":    "tag1,tag2",
+				"qiitaId": "qiita-123",
+				"content": "<p>content</p>",
+			},
+		},
+		{
+			name: "custom mapping with front matter key",
+			mapping: FieldMapping{
+				AttrQiitaID: "sourceId",
+				AttrContent: "body",
+				"slug":      "slug",
+			},
+			fields: Fields{
+				AttrTitle:   "Title",
+				AttrQiitaID: "qiita-123",
+				AttrContent: "<p>content</p>",
+				"slug":      "my-article",
+			},
+			expected: map[string]interface{}{
+				"sourceId": "qiita-123",
+				"body":     "<p>content</p>",
+				"slug":     "my-article",
+			},
+		},
+		{
+			name:    "missing attribute is not sent",
+			mapping: FieldMapping{AttrQiitaID: "qiitaId", "slug": "slug"},
+			fields:  Fields{AttrQiitaID: "qiita-123"},
+			expected: map[string]interface{}{
+				"qiitaId": "qiita-123",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := tt.mapping.Payload(tt.fields)
+
+			if !reflect.DeepEqual(tt.expected, payload) {
+				t.Errorf("Payload() = %v, want %v", payload, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFieldMapping_Diff(t *testing.T) {
+	mapping := FieldMapping{
+		AttrTitle:   "title",
+		AttrQiitaID: "qiitaId",
+		AttrContent: "body",
+		"tags":      "tags",
+	}
+
+	tests := []struct {
+		name     string
+		existing map[string]interface{}
+		fields   Fields
+		expected []FieldDiff
+	}{
+		{
+			name: "no difference",
+			existing: map[string]interface{}{
+				"id":        "content-id",
+				"title":     "Title",
+				"qiitaId":   "qiita-123",
+				"body":      "<p>content</p>",
+				"tags":      []interface{}{"a", "b"},
+				"updatedAt": "2025-03-23T11:50:41.000Z",
+			},
+			fields: Fields{
+				AttrTitle:   "Title",
+				AttrQiitaID: "qiita-123",
+				AttrContent: "<p>content</p>",
+				"tags":      []string{"a", "b"},
+			},
+			expected: []FieldDiff{},
+		},
+		{
+			name: "changed and missing fields",
+			existing: map[string]interface{}{
+				"title":   "Old Title",
+				"qiitaId": "qiita-123",
+			},
+			fields: Fields{
+				AttrTitle:   "New Title",
+				AttrQiitaID: "qiita-123",
+				AttrContent: "<p>content</p>",
+			},
+			expected: []FieldDiff{
+				{Field: "body", Old: nil, New: "<p>content</p>"},
+				{Field: "title", Old: "Old Title", New: "New Title"},
+			},
+		},
+		{
+			name: "reference fields are compared by id",
+			existing: map[string]interface{}{
+				"title":   "Title",
+				"qiitaId": "qiita-123",
+				"tags": []interface{}{
+					map[string]interface{}{"id": "tag-go", "name": "Go"},
+					map[string]interface{}{"id": "tag-cms", "name": "microCMS"},
+				},
+			},
+			fields: Fields{
+				AttrTitle:   "Title",
+				AttrQiitaID: "qiita-123",
+				"tags":      []string{"tag-go"},
+			},
+			expected: []FieldDiff{
+				{Field: "tags", Old: []interface{}{"tag-go", "tag-cms"}, New: []interface{}{"tag-go"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs := mapping.Diff(tt.existing, tt.fields)
+
+			if !reflect.DeepEqual(tt.expected, diffs) {
+				t.Errorf("Diff() = %v, want %v", diffs, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	got := FormatTime(time.Date(2025, 3, 23, 20, 50, 41, 0, jst))
+
+	if got != "2025-03-23T11:50:41.000Z" {
+		t.Errorf("FormatTime() = %s, want 2025-03-23T11:50:41.000Z", got)
+	}
+}
@@ -0,0 +1,118 @@
+package cms
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// listLimit はMicroCMSの一覧取得で1回に取得できる最大件数
+const listLimit = 100
+
+// ListOptions は一覧取得の条件。ゼロ値の項目はクエリに含めない
+type ListOptions struct {
+	// 取得件数（最大100）。ListAllでは1ページあたりの件数になる
+	Limit  int
+	Offset int
+	// 取得するフィールドID
+	Fields []string
+	// MicroCMSのfilters（例: `tags[contains]Go[and]private[equals]false`）
+	Filters string
+	// 並び替えに使うフィールドID（降順の場合は先頭に `-` を付ける）
+	Orders []string
+}
+
+// ListResponse は一覧取得のレスポンス
+type ListResponse struct {
+	Contents   []map[string]interface{} `json:"contents"`
+	TotalCount int                      `json:"totalCount"`
+	Offset     int                      `json:"offset"`
+	Limit      int                      `json:"limit"`
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		query.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if len(o.Fields) > 0 {
+		query.Set("fields", strings.Join(o.Fields, ","))
+	}
+	if o.Filters != "" {
+		query.Set("filters", o.Filters)
+	}
+	if len(o.Orders) > 0 {
+		query.Set("orders", strings.Join(o.Orders, ","))
+	}
+	return query
+}
+
+// List は条件に一致するコンテンツを1ページ分取得する
+func (c *Client) List(ctx context.Context, opts ListOptions) (*ListResponse, error) {
+	apiUrl := c.baseURL
+	if query := opts.query().Encode(); query != "" {
+		apiUrl = fmt.Sprintf("%s?%s", apiUrl, query)
+	}
+
+	var response ListResponse
+	if err := c.sendRequest(ctx, http.MethodGet, apiUrl, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListAll は条件に一致するすべてのコンテンツを、ページを順に取得しながら返す
+// エラーが発生した場合は、エラーを返して終了する
+func (c *Client) ListAll(ctx context.Context, opts ListOptions) iter.Seq2[map[string]interface{}, error] {
+	if opts.Limit <= 0 {
+		opts.Limit = listLimit
+	}
+
+	return func(yield func(map[string]interface{}, error) bool) {
+		for {
+			response, err := c.List(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, content := range response.Contents {
+				if !yield(content, nil) {
+					return
+				}
+			}
+
+			opts.Offset += len(response.Contents)
+			if len(response.Contents) == 0 || opts.Offset >= response.TotalCount {
+				return
+			}
+		}
+	}
+}
+
+// ListQiitaIDs はすべてのコンテンツを取得し、qiitaIdをキーとしたコンテンツIDを返す
+// qiitaIdが設定されていないコンテンツは含めない
+func (c *Client) ListQiitaIDs(ctx context.Context) (map[string]string, error) {
+	fieldID := c.mapping[AttrQiitaID]
+
+	ids := make(map[string]string)
+	for content, err := range c.ListAll(ctx, ListOptions{Fields: []string{"id", fieldID}}) {
+		if err != nil {
+			return nil, err
+		}
+
+		id, _ := content["id"].(string)
+		qiitaID, _ := content[fieldID].(string)
+		if id != "" && qiitaID != "" {
+			ids[qiitaID] = id
+		}
+	}
+	return ids, nil
+}
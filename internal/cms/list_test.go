@@ -0,0 +1,181 @@
+package cms
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_List(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ListOptions
+		wantQuery string
+	}{
+		{
+			name:      "no options",
+			opts:      ListOptions{},
+			wantQuery: "",
+		},
+		{
+			name: "all options",
+			opts: ListOptions{
+				Limit:   10,
+				Offset:  20,
+				Fields:  []string{"id", "title"},
+				Filters: "tags[contains]Go",
+				Orders:  []string{"-publishedAt", "title"},
+			},
+			wantQuery: "fields=id%2Ctitle&filters=tags%5Bcontains%5DGo&limit=10&offset=20&orders=-publishedAt%2Ctitle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					if req.Method != http.MethodGet {
+						t.Errorf("Expected method GET, got %s", req.Method)
+					}
+					if req.URL.RawQuery != tt.wantQuery {
+						t.Errorf("query = %s, want %s", req.URL.RawQuery, tt.wantQuery)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"contents": [{"id": "id-1"}], "totalCount": 30, "offset": 20, "limit": 10}`)),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+			response, err := client.List(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			want := &ListResponse{
+				Contents:   []map[string]interface{}{{"id": "id-1"}},
+				TotalCount: 30,
+				Offset:     20,
+				Limit:      10,
+			}
+			if !reflect.DeepEqual(response, want) {
+				t.Errorf("List() = %v, want %v", response, want)
+			}
+		})
+	}
+}
+
+// newPagesMock はoffsetをキーとしたレスポンスを返す
+func newPagesMock(t *testing.T, pages map[string]string, requests *int) *MockHTTPClient {
+	return &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			*requests++
+
+			body, ok := pages[req.URL.Query().Get("offset")]
+			if !ok {
+				t.Fatalf("Unexpected offset %s", req.URL.Query().Get("offset"))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		},
+	}
+}
+
+func TestClient_ListAll(t *testing.T) {
+	pages := map[string]string{
+		"":  `{"totalCount": 3, "offset": 0, "limit": 2, "contents": [{"id": "id-1"}, {"id": "id-2"}]}`,
+		"2": `{"totalCount": 3, "offset": 2, "limit": 2, "contents": [{"id": "id-3"}]}`,
+	}
+
+	t.Run("walks all pages", func(t *testing.T) {
+		requests := 0
+		client := NewClient("service-id", "test-api-key", "endpoint", newPagesMock(t, pages, &requests))
+
+		ids := make([]string, 0)
+		for content, err := range client.ListAll(context.Background(), ListOptions{Limit: 2}) {
+			if err != nil {
+				t.Fatalf("ListAll() error = %v", err)
+			}
+			ids = append(ids, content["id"].(string))
+		}
+
+		if want := []string{"id-1", "id-2", "id-3"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("ListAll() = %v, want %v", ids, want)
+		}
+		if requests != 2 {
+			t.Errorf("requests = %d, want 2", requests)
+		}
+	})
+
+	t.Run("stops when the caller breaks", func(t *testing.T) {
+		requests := 0
+		client := NewClient("service-id", "test-api-key", "endpoint", newPagesMock(t, pages, &requests))
+
+		for range client.ListAll(context.Background(), ListOptions{Limit: 2}) {
+			break
+		}
+
+		if requests != 1 {
+			t.Errorf("requests = %d, want 1", requests)
+		}
+	})
+
+	t.Run("returns API error", func(t *testing.T) {
+		mockClient := &MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       io.NopCloser(strings.NewReader(`{"message": "Internal server error"}`)),
+				}, nil
+			},
+		}
+		client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+
+		errs := 0
+		for content, err := range client.ListAll(context.Background(), ListOptions{}) {
+			if err == nil {
+				t.Errorf("Unexpected content %v", content)
+				continue
+			}
+			errs++
+		}
+		if errs != 1 {
+			t.Errorf("errors = %d, want 1", errs)
+		}
+	})
+}
+
+func TestClient_ListQiitaIDs(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			if query.Get("fields") != "id,qiitaId" {
+				t.Errorf("Unexpected fields %s", query.Get("fields"))
+			}
+			if query.Get("limit") != "100" {
+				t.Errorf("Unexpected limit %s", query.Get("limit"))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"totalCount": 2, "offset": 0, "limit": 100, "contents": [{"id": "id-1", "qiitaId": "qiita-1"}, {"id": "id-2"}]}`)),
+			}, nil
+		},
+	}
+
+	client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+	ids, err := client.ListQiitaIDs(context.Background())
+	if err != nil {
+		t.Fatalf("ListQiitaIDs() error = %v", err)
+	}
+
+	// qiitaIdが設定されていないコンテンツは含めない
+	if want := map[string]string{"qiita-1": "id-1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListQiitaIDs() = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,54 @@
+package cms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ContentManager はmicroCMSのマネジメントAPIを使ってコンテンツの公開状態を取得・変更する
+//
+// コンテンツAPIの `?status=draft` では公開中のコンテンツに下書きが追加されるだけで公開は止まらないため、
+// 公開中のコンテンツを下書きに戻す場合に使う
+type ContentManager struct {
+	// マネジメントAPIへのリクエストを送信するクライアント（コンテンツAPIと同じ再試行・リクエスト数の制限を適用する）
+	client   *Client
+	endpoint string
+}
+
+type contentStatusBody struct {
+	Status []ContentStatus `json:"status"`
+}
+
+// NewContentManager はエンドポイントのコンテンツを管理するクライアントを作成する
+// optsにはClientと同じオプションを指定でき、WithBaseURLはマネジメントAPIのURL（既定は `https://<サービスID>.microcms-management.io/api`）になる
+func NewContentManager(serviceID, apiKey, endpoint string, httpClient HTTPDoer, opts ...Option) *ContentManager {
+	opts = append([]Option{WithBaseURL(fmt.Sprintf("https://%s.microcms-management.io/api", serviceID))}, opts...)
+	return &ContentManager{
+		client:   NewClient(serviceID, apiKey, "", httpClient, opts...),
+		endpoint: endpoint,
+	}
+}
+
+// Status はコンテンツの公開状態を返す
+func (m *ContentManager) Status(ctx context.Context, id string) (ContentStatus, error) {
+	var response contentStatusBody
+	if err := m.client.sendRequest(ctx, http.MethodGet, m.contentURL(id), nil, &response); err != nil {
+		return "", err
+	}
+	if len(response.Status) == 0 {
+		return "", errors.New("the content status is empty")
+	}
+	return response.Status[0], nil
+}
+
+// SetStatus はコンテンツの公開状態をStatusPublishまたはStatusDraftに変更する
+func (m *ContentManager) SetStatus(ctx context.Context, id string, status ContentStatus) error {
+	body := contentStatusBody{Status: []ContentStatus{status}}
+	return m.client.sendRequest(ctx, http.MethodPatch, m.contentURL(id)+"/status", body, nil)
+}
+
+func (m *ContentManager) contentURL(id string) string {
+	return fmt.Sprintf("%s/v1/contents/%s/%s", m.client.apiBase, m.endpoint, id)
+}
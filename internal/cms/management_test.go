@@ -0,0 +1,96 @@
+package cms
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestContentManager_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     ContentStatus
+		wantErr  bool
+	}{
+		{
+			name:     "公開中",
+			response: `{"id": "test-id", "status": ["PUBLISH"]}`,
+			want:     StatusPublish,
+		},
+		{
+			name:     "公開中で下書きあり",
+			response: `{"id": "test-id", "status": ["PUBLISH_AND_DRAFT"]}`,
+			want:     StatusPublishAndDraft,
+		},
+		{
+			name:     "公開状態がない",
+			response: `{"id": "test-id", "status": []}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					expectedURL := "https://service-id.microcms-management.io/api/v1/contents/endpoint/test-id"
+					if req.URL.String() != expectedURL {
+						t.Errorf("Expected URL %s, got %s", expectedURL, req.URL.String())
+					}
+					if req.Method != http.MethodGet {
+						t.Errorf("Expected method GET, got %s", req.Method)
+					}
+					if req.Header.Get("X-MICROCMS-API-KEY") != "test-api-key" {
+						t.Errorf("Expected API key test-api-key, got %s", req.Header.Get("X-MICROCMS-API-KEY"))
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(tt.response)),
+					}, nil
+				},
+			}
+
+			manager := NewContentManager("service-id", "test-api-key", "endpoint", mockClient)
+			got, err := manager.Status(context.Background(), "test-id")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Status() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Status() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentManager_SetStatus(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			expectedURL := "http://localhost:8080/api/v1/contents/endpoint/test-id/status"
+			if req.URL.String() != expectedURL {
+				t.Errorf("Expected URL %s, got %s", expectedURL, req.URL.String())
+			}
+			if req.Method != http.MethodPatch {
+				t.Errorf("Expected method PATCH, got %s", req.Method)
+			}
+
+			body, _ := io.ReadAll(req.Body)
+			if string(body) != `{"status":["DRAFT"]}` {
+				t.Errorf("Expected body {\"status\":[\"DRAFT\"]}, got %s", body)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id": "test-id"}`)),
+			}, nil
+		},
+	}
+
+	manager := NewContentManager("service-id", "test-api-key", "endpoint", mockClient, WithBaseURL("http://localhost:8080/api"))
+	if err := manager.SetStatus(context.Background(), "test-id", StatusDraft); err != nil {
+		t.Errorf("SetStatus() error = %v", err)
+	}
+}
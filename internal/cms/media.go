@@ -0,0 +1,178 @@
+package cms
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// ファイル名に付けるハッシュの長さ
+const mediaHashLength = 16
+
+// MediaUploader はmicroCMSのマネジメントAPIを使って画像をメディアに登録する
+type MediaUploader struct {
+	// マネジメントAPIへのリクエストを送信するクライアント（コンテンツAPIと同じ再試行・リクエスト数の制限を適用する）
+	client *Client
+	// 同じ内容の画像を何度もアップロードしないよう、内容のハッシュごとにアップロード中・完了したものを保持する
+	// 記事を並行して処理する場合も、異なる画像は並行してアップロードする
+	uploads map[string]*mediaUpload
+	mu      sync.Mutex
+}
+
+// mediaUpload は1つの画像のアップロード。doneが閉じられた後にurl・errを参照できる
+type mediaUpload struct {
+	done chan struct{}
+	url  string
+	err  error
+}
+
+type UploadMediaResponse struct {
+	URL string `json:"url"`
+}
+
+type Media struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+type ListMediaResponse struct {
+	Media      []Media `json:"media"`
+	TotalCount int     `json:"totalCount"`
+}
+
+// NewMediaUploader はマネジメントAPIのクライアントを作成する
+// optsにはClientと同じオプションを指定でき、WithBaseURLはマネジメントAPIのURL（既定は `https://<サービスID>.microcms-management.io/api`）になる
+func NewMediaUploader(serviceID, apiKey string, httpClient HTTPDoer, opts ...Option) *MediaUploader {
+	opts = append([]Option{WithBaseURL(fmt.Sprintf("https://%s.microcms-management.io/api", serviceID))}, opts...)
+	return &MediaUploader{
+		client:  NewClient(serviceID, apiKey, "", httpClient, opts...),
+		uploads: make(map[string]*mediaUpload),
+	}
+}
+
+func (u *MediaUploader) UploadFile(ctx context.Context, filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read media file: %w", err)
+	}
+
+	return u.Upload(ctx, filepath.Base(filePath), content)
+}
+
+// Upload は画像をアップロードしてURLを返す
+//
+// ファイル名の先頭に内容のハッシュを付けてアップロードし、同じファイル名のメディアが
+// 既に登録されている場合はそのURLを返す。実行のたびにURLが変わらないようにするため
+func (u *MediaUploader) Upload(ctx context.Context, filename string, content []byte) (string, error) {
+	hash := contentHash(content)
+
+	for {
+		u.mu.Lock()
+		upload, ok := u.uploads[hash]
+		if !ok {
+			upload = &mediaUpload{done: make(chan struct{})}
+			u.uploads[hash] = upload
+		}
+		u.mu.Unlock()
+
+		if !ok {
+			upload.url, upload.err = u.upload(ctx, mediaFilename(hash, filename), content)
+			if upload.err != nil {
+				// 失敗した場合は、他の記事から改めてアップロードできるようにする
+				u.mu.Lock()
+				delete(u.uploads, hash)
+				u.mu.Unlock()
+			}
+			close(upload.done)
+			return upload.url, upload.err
+		}
+
+		// 他の記事で同じ画像をアップロードしている場合は、完了を待つ
+		select {
+		case <-upload.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if upload.err == nil {
+			return upload.url, nil
+		}
+	}
+}
+
+// LookupFile はファイルと同じ内容の画像がアップロード済みであればそのURLを、なければ空文字を返す
+// アップロードは行わないため、書き込まずに計画を出力する場合に使う
+func (u *MediaUploader) LookupFile(ctx context.Context, filePath string) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read media file: %w", err)
+	}
+
+	return u.find(ctx, mediaFilename(contentHash(content), filepath.Base(filePath)))
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// mediaFilename はアップロードするファイル名（内容のハッシュを先頭に付けたもの）を返す
+func mediaFilename(hash, filename string) string {
+	return fmt.Sprintf("%s-%s", hash[:mediaHashLength], filename)
+}
+
+// upload は同じファイル名のメディアがなければアップロードし、URLを返す
+func (u *MediaUploader) upload(ctx context.Context, filename string, content []byte) (string, error) {
+	if mediaURL, err := u.find(ctx, filename); err == nil && mediaURL != "" {
+		return mediaURL, nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to create multipart body: %w", err)
+	}
+
+	var response UploadMediaResponse
+	if err := u.client.send(ctx, http.MethodPost, u.client.apiBase+"/v1/media", body.Bytes(), writer.FormDataContentType(), &response); err != nil {
+		return "", err
+	}
+	return response.URL, nil
+}
+
+// find はファイル名が一致するメディアのURLを返す。見つからない場合は空文字を返す
+func (u *MediaUploader) find(ctx context.Context, filename string) (string, error) {
+	apiUrl := fmt.Sprintf("%s/v2/media?fileName=%s", u.client.apiBase, url.QueryEscape(filename))
+
+	var response ListMediaResponse
+	if err := u.client.sendRequest(ctx, http.MethodGet, apiUrl, nil, &response); err != nil {
+		return "", err
+	}
+
+	// fileNameは部分一致のため、URLのファイル名が完全に一致するものを探す
+	for _, media := range response.Media {
+		mediaURL, err := url.Parse(media.URL)
+		if err != nil {
+			continue
+		}
+		if path.Base(mediaURL.Path) == filename {
+			return media.URL, nil
+		}
+	}
+	return "", nil
+}
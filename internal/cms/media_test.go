@@ -0,0 +1,358 @@
+package cms
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewMediaUploader(t *testing.T) {
+	mockClient := &MockHTTPClient{}
+	uploader := NewMediaUploader("service-id", "test-api-key", mockClient)
+
+	if uploader == nil {
+		t.Fatal("Expected uploader to be initialized, got nil")
+	}
+}
+
+func TestMediaUploader_Options(t *testing.T) {
+	var requests []string
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req.Method+" "+req.URL.String())
+			if req.Header.Get("User-Agent") != "test-agent" {
+				t.Errorf("Expected User-Agent test-agent, got %s", req.Header.Get("User-Agent"))
+			}
+			if req.Method == http.MethodGet {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"media": [], "totalCount": 0}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"url": "https://images.microcms-assets.io/assets/xxx/yyy/6105d6cc76af4003-sample.png"}`)),
+			}, nil
+		},
+	}
+
+	uploader := NewMediaUploader("service-id", "test-api-key", nil,
+		WithHTTPClient(mockClient),
+		WithBaseURL("http://localhost:8080/management/"),
+		WithUserAgent("test-agent"),
+	)
+	if _, err := uploader.Upload(context.Background(), "sample.png", []byte("image")); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	want := []string{
+		"GET http://localhost:8080/management/v2/media?fileName=6105d6cc76af4003-sample.png",
+		"POST http://localhost:8080/management/v1/media",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestMediaUploader_Upload(t *testing.T) {
+	tests := []struct {
+		name       string
+		listBody   string
+		statusCode int
+		respBody   string
+		wantURL    string
+		wantUpload bool
+		wantErr    bool
+	}{
+		{
+			name:       "successful upload",
+			listBody:   `{"media": [], "totalCount": 0}`,
+			statusCode: http.StatusCreated,
+			respBody:   `{"url": "https://images.microcms-assets.io/assets/xxx/yyy/6105d6cc76af4003-sample.png"}`,
+			wantURL:    "https://images.microcms-assets.io/assets/xxx/yyy/6105d6cc76af4003-sample.png",
+			wantUpload: true,
+			wantErr:    false,
+		},
+		{
+			name:       "already uploaded",
+			listBody:   `{"media": [{"id": "a", "url": "https://images.microcms-assets.io/assets/xxx/zzz/x6105d6cc76af4003-sample.png"}, {"id": "b", "url": "https://images.microcms-assets.io/assets/xxx/yyy/6105d6cc76af4003-sample.png"}], "totalCount": 2}`,
+			statusCode: http.StatusCreated,
+			respBody:   ``,
+			wantURL:    "https://images.microcms-assets.io/assets/xxx/yyy/6105d6cc76af4003-sample.png",
+			wantUpload: false,
+			wantErr:    false,
+		},
+		{
+			name:       "forbidden",
+			listBody:   `{"media": [], "totalCount": 0}`,
+			statusCode: http.StatusForbidden,
+			respBody:   `{"message": "Forbidden"}`,
+			wantURL:    "",
+			wantUpload: true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploaded := false
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					// ヘッダーの検証
+					if req.Header.Get("X-MICROCMS-API-KEY") != "test-api-key" {
+						t.Errorf("Expected API key header, got %s", req.Header.Get("X-MICROCMS-API-KEY"))
+					}
+
+					// 登録済みのメディアの検索
+					if req.Method == http.MethodGet {
+						expectedURL := "https://service-id.microcms-management.io/api/v2/media?fileName=6105d6cc76af4003-sample.png"
+						if req.URL.String() != expectedURL {
+							t.Errorf("Expected URL %s, got %s", expectedURL, req.URL.String())
+						}
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader(tt.listBody)),
+						}, nil
+					}
+
+					// リクエストURLの検証
+					expectedURL := "https://service-id.microcms-management.io/api/v1/media"
+					if req.URL.String() != expectedURL {
+						t.Errorf("Expected URL %s, got %s", expectedURL, req.URL.String())
+					}
+
+					// HTTPメソッドの検証
+					if req.Method != http.MethodPost {
+						t.Errorf("Expected method POST, got %s", req.Method)
+					}
+
+					// マルチパートのファイルの検証
+					file, header, err := req.FormFile("file")
+					if err != nil {
+						t.Fatalf("Failed to read multipart file: %v", err)
+					}
+					content, _ := io.ReadAll(file)
+					if header.Filename != "6105d6cc76af4003-sample.png" || string(content) != "image" {
+						t.Errorf("Unexpected file %s: %s", header.Filename, string(content))
+					}
+
+					uploaded = true
+
+					// レスポンスの作成
+					return &http.Response{
+						StatusCode: tt.statusCode,
+						Body:       io.NopCloser(strings.NewReader(tt.respBody)),
+					}, nil
+				},
+			}
+
+			uploader := NewMediaUploader("service-id", "test-api-key", mockClient)
+			url, err := uploader.Upload(context.Background(), "sample.png", []byte("image"))
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Upload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if url != tt.wantURL {
+				t.Errorf("Upload() url = %v, want %v", url, tt.wantURL)
+			}
+
+			if uploaded != tt.wantUpload {
+				t.Errorf("Upload() uploaded = %v, want %v", uploaded, tt.wantUpload)
+			}
+		})
+	}
+}
+
+func TestMediaUploader_UploadDeduplicates(t *testing.T) {
+	calls := 0
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			if req.Method == http.MethodGet {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"media": [], "totalCount": 0}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"url": "https://images.microcms-assets.io/assets/xxx/yyy/0967115f2813a354-a.png"}`)),
+			}, nil
+		},
+	}
+
+	uploader := NewMediaUploader("service-id", "test-api-key", mockClient)
+
+	// ファイル名が違っても内容が同じなら1度だけアップロードする
+	first, err := uploader.Upload(context.Background(), "a.png", []byte("same"))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	second, err := uploader.Upload(context.Background(), "b.png", []byte("same"))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 requests (search and upload), got %d", calls)
+	}
+	if first != second {
+		t.Errorf("Expected same url, got %s and %s", first, second)
+	}
+}
+
+func TestMediaUploader_UploadConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	uploads := make(map[string]int)
+	// 異なる画像のアップロードが並行して行われることを確認するため、bのアップロードが始まるまでaを待たせる
+	started := make(chan struct{})
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"media": [], "totalCount": 0}`)),
+				}, nil
+			}
+
+			file, _, err := req.FormFile("file")
+			if err != nil {
+				t.Errorf("Failed to read multipart file: %v", err)
+			}
+			content, _ := io.ReadAll(file)
+			mu.Lock()
+			uploads[string(content)]++
+			mu.Unlock()
+
+			switch string(content) {
+			case "a":
+				select {
+				case <-started:
+				case <-time.After(time.Second):
+					t.Error("uploads are not concurrent")
+				}
+			case "b":
+				close(started)
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"url": "https://images.microcms-assets.io/assets/xxx/yyy/` + string(content) + `.png"}`)),
+			}, nil
+		},
+	}
+
+	uploader := NewMediaUploader("service-id", "test-api-key", mockClient)
+
+	var wg sync.WaitGroup
+	for _, content := range []string{"a", "a", "a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			url, err := uploader.Upload(context.Background(), "image.png", []byte(content))
+			if err != nil {
+				t.Errorf("Upload() error = %v", err)
+			}
+			if want := "https://images.microcms-assets.io/assets/xxx/yyy/" + content + ".png"; url != want {
+				t.Errorf("Upload() url = %v, want %v", url, want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 同じ内容の画像は1度だけアップロードする
+	if want := map[string]int{"a": 1, "b": 1}; !reflect.DeepEqual(uploads, want) {
+		t.Errorf("uploads = %v, want %v", uploads, want)
+	}
+}
+
+func TestMediaUploader_WithRateLimiter(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"media": [], "totalCount": 0}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"url": "https://images.microcms-assets.io/assets/xxx/yyy/sample.png"}`)),
+			}, nil
+		},
+	}
+
+	waits := make([]time.Duration, 0)
+	limiter := newTestRateLimiter(DefaultReadRate, DefaultWriteRate, &waits)
+	// コンテンツAPIとマネジメントAPIで上限を共有する
+	items := NewClient("service-id", "test-api-key", "items", mockClient, WithRateLimiter(limiter))
+	uploader := NewMediaUploader("service-id", "test-api-key", mockClient, WithRateLimiter(limiter))
+
+	if err := items.Delete(context.Background(), "id-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := uploader.Upload(context.Background(), "sample.png", []byte("image")); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if want := []time.Duration{200 * time.Millisecond}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("waits = %v, want %v", waits, want)
+	}
+}
+
+func TestMediaUploader_LookupFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		listBody string
+		wantURL  string
+	}{
+		{
+			name:     "uploaded",
+			listBody: `{"media": [{"id": "a", "url": "https://images.microcms-assets.io/assets/xxx/yyy/6105d6cc76af4003-sample.png"}], "totalCount": 1}`,
+			wantURL:  "https://images.microcms-assets.io/assets/xxx/yyy/6105d6cc76af4003-sample.png",
+		},
+		{
+			name:     "not uploaded",
+			listBody: `{"media": [], "totalCount": 0}`,
+			wantURL:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					// 検索だけを行い、アップロードしない
+					if req.Method != http.MethodGet {
+						t.Errorf("Expected method GET, got %s", req.Method)
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(tt.listBody)),
+					}, nil
+				},
+			}
+
+			path := filepath.Join(t.TempDir(), "sample.png")
+			if err := os.WriteFile(path, []byte("image"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			uploader := NewMediaUploader("service-id", "test-api-key", mockClient)
+			url, err := uploader.LookupFile(context.Background(), path)
+			if err != nil {
+				t.Fatalf("LookupFile() error = %v", err)
+			}
+			if url != tt.wantURL {
+				t.Errorf("LookupFile() url = %v, want %v", url, tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package cms
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MicroCMSのコンテンツAPIへの1秒あたりのリクエスト数の既定値
+// 書き込み系（POST・PUT・PATCH・DELETE）は上限が低いため、取得系と分けて制限する
+const (
+	DefaultReadRate  = 60
+	DefaultWriteRate = 5
+)
+
+// RateLimiter はリクエストの送信間隔を制限する
+// 記事を並行して処理する場合も上限を超えないよう、複数のクライアントで共有する
+type RateLimiter struct {
+	mu    sync.Mutex
+	read  rateLimit
+	write rateLimit
+
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+type rateLimit struct {
+	interval time.Duration
+	// 次のリクエストを送信できる時刻
+	next time.Time
+}
+
+// NewRateLimiter は取得系・書き込み系それぞれ、1秒あたりのリクエスト数を指定して作成する
+// 0以下の場合は制限しない
+func NewRateLimiter(readPerSecond, writePerSecond float64) *RateLimiter {
+	return &RateLimiter{
+		read:  rateLimit{interval: interval(readPerSecond)},
+		write: rateLimit{interval: interval(writePerSecond)},
+		now:   time.Now,
+		sleep: sleep,
+	}
+}
+
+func interval(perSecond float64) time.Duration {
+	if perSecond <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / perSecond)
+}
+
+// WithRateLimiter はリクエストの送信間隔を制限する。再試行のリクエストも制限の対象とする
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Wait はリクエストを送信できるまで待機する
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	if l == nil {
+		return nil
+	}
+
+	limit := &l.write
+	if method == http.MethodGet {
+		limit = &l.read
+	}
+
+	// 送信する時刻を予約してから待機し、同時に呼び出された場合も間隔を空ける
+	l.mu.Lock()
+	now := l.now()
+	at := limit.next
+	if at.Before(now) {
+		at = now
+	}
+	limit.next = at.Add(limit.interval)
+	l.mu.Unlock()
+
+	if wait := at.Sub(now); wait > 0 {
+		return l.sleep(ctx, wait)
+	}
+	return ctx.Err()
+}
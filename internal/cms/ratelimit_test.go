@@ -0,0 +1,101 @@
+package cms
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRateLimiter は待機せず、待機時間を記録して時刻を進める
+func newTestRateLimiter(readPerSecond, writePerSecond float64, waits *[]time.Duration) *RateLimiter {
+	l := NewRateLimiter(readPerSecond, writePerSecond)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return l
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	tests := []struct {
+		name    string
+		methods []string
+		want    []time.Duration
+	}{
+		{
+			name:    "writes are spaced by the write rate",
+			methods: []string{http.MethodPost, http.MethodPatch, http.MethodDelete},
+			want:    []time.Duration{200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:    "reads and writes are limited separately",
+			methods: []string{http.MethodGet, http.MethodPost, http.MethodGet, http.MethodPost},
+			want:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits := make([]time.Duration, 0)
+			l := newTestRateLimiter(10, 5, &waits)
+
+			for _, method := range tt.methods {
+				if err := l.Wait(context.Background(), method); err != nil {
+					t.Fatalf("Wait() error = %v", err)
+				}
+			}
+
+			if !reflect.DeepEqual(waits, tt.want) {
+				t.Errorf("waits = %v, want %v", waits, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	waits := make([]time.Duration, 0)
+	l := newTestRateLimiter(0, 0, &waits)
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), http.MethodPost); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if len(waits) != 0 {
+		t.Errorf("waits = %v, want none", waits)
+	}
+}
+
+func TestClient_WithRateLimiter(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusAccepted,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	waits := make([]time.Duration, 0)
+	limiter := newTestRateLimiter(DefaultReadRate, DefaultWriteRate, &waits)
+	// 複数のクライアントで上限を共有する
+	items := NewClient("service-id", "test-api-key", "items", mockClient, WithRateLimiter(limiter))
+	tags := NewClient("service-id", "test-api-key", "tags", mockClient, WithRateLimiter(limiter))
+
+	if err := items.Delete(context.Background(), "id-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := tags.Delete(context.Background(), "id-2"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if want := []time.Duration{200 * time.Millisecond}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("waits = %v, want %v", waits, want)
+	}
+}
@@ -0,0 +1,98 @@
+package cms
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy は429や5xxが返された場合の再試行の設定
+type RetryPolicy struct {
+	// 最初のリクエストを含む試行回数（1以下の場合は再試行しない）
+	MaxAttempts int
+	// 1回目の再試行までの待機時間。以降は再試行のたびに2倍にする
+	BaseDelay time.Duration
+	// 待機時間の上限（Retry-Afterが指定された場合はそちらを優先する）
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy はMicroCMSのレート制限が解除されるまで待てるよう、最大で約30秒待機する
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Second,
+	MaxDelay:    15 * time.Second,
+}
+
+// WithRetryPolicy は再試行の設定を指定する。指定しない場合は再試行しない
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// shouldRetry は再試行してよいかを判定する
+//
+// POSTは処理されたかわからない状態で再送するとコンテンツが重複するため、
+// リクエストが処理されていないことが明らかな429の場合のみ再試行する
+func shouldRetry(method string, statusCode int) bool {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return true
+	case method == http.MethodPost:
+		return false
+	case statusCode == 0, statusCode >= 500:
+		// 0は通信エラー
+		return true
+	default:
+		return false
+	}
+}
+
+// delay はattempt回目の試行が失敗した後の待機時間を返す
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	// 複数の実行が同時に再試行しないよう、待機時間の後半をランダムにする
+	half := backoff / 2
+	if half <= 0 {
+		return backoff
+	}
+	return half + rand.N(half)
+}
+
+// parseRetryAfter はRetry-Afterヘッダー（秒数またはHTTP日付）を待機時間に変換する
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
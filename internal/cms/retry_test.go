@@ -0,0 +1,322 @@
+package cms
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Retry(t *testing.T) {
+	type response struct {
+		statusCode int
+		retryAfter string
+		err        error
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		responses    []response
+		wantAttempts int
+		wantDelays   []time.Duration
+		wantErr      bool
+	}{
+		{
+			name:   "GET succeeds after server error",
+			method: http.MethodGet,
+			responses: []response{
+				{statusCode: http.StatusServiceUnavailable},
+				{statusCode: http.StatusOK},
+			},
+			wantAttempts: 2,
+			wantErr:      false,
+		},
+		{
+			name:   "GET gives up after max attempts",
+			method: http.MethodGet,
+			responses: []response{
+				{statusCode: http.StatusInternalServerError},
+				{statusCode: http.StatusInternalServerError},
+				{statusCode: http.StatusInternalServerError},
+				{statusCode: http.StatusOK},
+			},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:   "GET retries on transport error",
+			method: http.MethodGet,
+			responses: []response{
+				{err: errors.New("connection reset")},
+				{statusCode: http.StatusOK},
+			},
+			wantAttempts: 2,
+			wantErr:      false,
+		},
+		{
+			name:   "GET does not retry on client error",
+			method: http.MethodGet,
+			responses: []response{
+				{statusCode: http.StatusBadRequest},
+				{statusCode: http.StatusOK},
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:   "PATCH honors Retry-After",
+			method: http.MethodPatch,
+			responses: []response{
+				{statusCode: http.StatusTooManyRequests, retryAfter: "7"},
+				{statusCode: http.StatusOK},
+			},
+			wantAttempts: 2,
+			wantDelays:   []time.Duration{7 * time.Second},
+			wantErr:      false,
+		},
+		{
+			name:   "POST retries on rate limit",
+			method: http.MethodPost,
+			responses: []response{
+				{statusCode: http.StatusTooManyRequests},
+				{statusCode: http.StatusCreated},
+			},
+			wantAttempts: 2,
+			wantErr:      false,
+		},
+		{
+			name:   "POST does not retry on server error",
+			method: http.MethodPost,
+			responses: []response{
+				{statusCode: http.StatusInternalServerError},
+				{statusCode: http.StatusCreated},
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:   "POST does not retry on transport error",
+			method: http.MethodPost,
+			responses: []response{
+				{err: errors.New("connection reset")},
+				{statusCode: http.StatusCreated},
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			mockClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					// HTTPメソッドの検証
+					if req.Method != tt.method {
+						t.Errorf("Expected method %s, got %s", tt.method, req.Method)
+					}
+
+					// 再試行でも同じリクエストボディを送る
+					if req.Method != http.MethodGet {
+						body, _ := io.ReadAll(req.Body)
+						if !strings.Contains(string(body), `"title":"Test Title"`) {
+							t.Errorf("Unexpected request body %s", string(body))
+						}
+					}
+
+					res := tt.responses[attempts]
+					attempts++
+					if res.err != nil {
+						return nil, res.err
+					}
+
+					header := make(http.Header)
+					if res.retryAfter != "" {
+						header.Set("Retry-After", res.retryAfter)
+					}
+					return &http.Response{
+						StatusCode: res.statusCode,
+						Header:     header,
+						Body:       io.NopCloser(strings.NewReader(`{"id": "test-id", "totalCount": 0, "contents": []}`)),
+					}, nil
+				},
+			}
+
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient, WithRetryPolicy(RetryPolicy{
+				MaxAttempts: 3,
+				BaseDelay:   time.Second,
+				MaxDelay:    10 * time.Second,
+			}))
+
+			// 実際には待機せず、待機時間を記録する
+			delays := make([]time.Duration, 0)
+			client.sleep = func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			fields := Fields{AttrTitle: "Test Title", AttrQiitaID: "qiita-123"}
+			var err error
+			switch tt.method {
+			case http.MethodGet:
+				_, _, err = client.CheckExists(context.Background(), "qiita-123")
+			case http.MethodPatch:
+				err = client.Update(context.Background(), "test-id", fields)
+			case http.MethodPost:
+				_, err = client.Create(context.Background(), fields)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+
+			if len(delays) != tt.wantAttempts-1 {
+				t.Errorf("delays = %v, want %d delay(s)", delays, tt.wantAttempts-1)
+			}
+			for i, want := range tt.wantDelays {
+				if delays[i] != want {
+					t.Errorf("delays[%d] = %v, want %v", i, delays[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_RetryDisabledByDefault(t *testing.T) {
+	attempts := 0
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       io.NopCloser(strings.NewReader(`{"message": "Too many requests"}`)),
+			}, nil
+		},
+	}
+
+	client := NewClient("service-id", "test-api-key", "endpoint", mockClient)
+	if _, _, err := client.CheckExists(context.Background(), "qiita-123"); err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestClient_WithRequestTimeout(t *testing.T) {
+	attempts := 0
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			attempts++
+			// 1回目は応答せず、タイムアウトさせる
+			if attempts == 1 {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"totalCount": 0, "contents": []}`)),
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		wantAttempts int
+		wantTimeout  bool
+	}{
+		{
+			name:         "retries with a new timeout",
+			maxAttempts:  2,
+			wantAttempts: 2,
+		},
+		{
+			name:         "returns deadline exceeded",
+			maxAttempts:  1,
+			wantAttempts: 1,
+			wantTimeout:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts = 0
+			client := NewClient("service-id", "test-api-key", "endpoint", mockClient,
+				WithRequestTimeout(10*time.Millisecond),
+				WithRetryPolicy(RetryPolicy{MaxAttempts: tt.maxAttempts}),
+			)
+			client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+			_, _, err := client.CheckExists(context.Background(), "qiita-123")
+			if got := errors.Is(err, context.DeadlineExceeded); got != tt.wantTimeout {
+				t.Errorf("error = %v, want timeout %v", err, tt.wantTimeout)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter time.Duration
+		min        time.Duration
+		max        time.Duration
+	}{
+		{name: "first retry", attempt: 1, min: 500 * time.Millisecond, max: time.Second},
+		{name: "second retry", attempt: 2, min: time.Second, max: 2 * time.Second},
+		{name: "capped by max delay", attempt: 4, min: 1500 * time.Millisecond, max: 3 * time.Second},
+		{name: "retry after", attempt: 1, retryAfter: 20 * time.Second, min: 20 * time.Second, max: 20 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				d := policy.delay(tt.attempt, tt.retryAfter)
+				if d < tt.min || d > tt.max {
+					t.Fatalf("delay() = %v, want between %v and %v", d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{name: "empty", value: "", min: 0, max: 0},
+		{name: "seconds", value: "5", min: 5 * time.Second, max: 5 * time.Second},
+		{name: "http date", value: future, min: 58 * time.Second, max: time.Minute},
+		{name: "invalid", value: "soon", min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := parseRetryAfter(tt.value)
+			if d < tt.min || d > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want between %v and %v", tt.value, d, tt.min, tt.max)
+			}
+		})
+	}
+}
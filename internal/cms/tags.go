@@ -0,0 +1,100 @@
+package cms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// TagResolver はタグ名を、タグを管理するAPIのコンテンツIDに変換する
+// 記事のタグを参照フィールドで登録する場合に使用する
+type TagResolver struct {
+	// タグを管理するAPIのクライアント
+	client *Client
+	// タグ名を保持するフィールドID
+	nameField string
+	// タグ名をキーとしたコンテンツID（同じ実行の中で何度も問い合わせないため）
+	ids map[string]string
+	// 記事を並行して処理する場合に、同じタグを重複して作成しないよう1件ずつ処理する
+	mu sync.Mutex
+}
+
+func NewTagResolver(client *Client, nameField string) *TagResolver {
+	return &TagResolver{
+		client:    client,
+		nameField: nameField,
+		ids:       make(map[string]string),
+	}
+}
+
+// Lookup はタグ名に対応するコンテンツIDを返す
+// 存在しないタグは作成せず、タグ名をmissingとして返す
+func (r *TagResolver) Lookup(ctx context.Context, names []string) ([]string, []string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]string, 0, len(names))
+	missing := make([]string, 0)
+	for _, name := range names {
+		id, err := r.find(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id == "" {
+			missing = append(missing, name)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, missing, nil
+}
+
+// Resolve はタグ名に対応するコンテンツIDを返す。存在しないタグはコンテンツを作成する
+func (r *TagResolver) Resolve(ctx context.Context, names []string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		id, err := r.find(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			if id, err = r.create(ctx, name); err != nil {
+				return nil, err
+			}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *TagResolver) find(ctx context.Context, name string) (string, error) {
+	if id, ok := r.ids[name]; ok {
+		return id, nil
+	}
+
+	id, _, err := r.client.findBy(ctx, r.nameField, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to find tag %s: %w", name, err)
+	}
+	if id != "" {
+		r.ids[name] = id
+	}
+	return id, nil
+}
+
+func (r *TagResolver) create(ctx context.Context, name string) (string, error) {
+	// タグのAPIは記事の対応表とは別のスキーマのため、フィールドIDを直接指定する
+	payload := map[string]interface{}{r.nameField: name}
+
+	var response CreateResponse
+	if err := r.client.sendRequest(ctx, http.MethodPost, r.client.baseURL, payload, &response); err != nil {
+		return "", fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+
+	r.ids[name] = response.ID
+	return response.ID, nil
+}
@@ -0,0 +1,113 @@
+package cms
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTagsMock はname[equals]で検索できるタグのAPIをモックする
+func newTagsMock(t *testing.T, tags map[string]string, created *[]string) *MockHTTPClient {
+	return &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.String(), "https://service-id.microcms.io/api/v1/tags") {
+				t.Errorf("Unexpected URL %s", req.URL.String())
+			}
+
+			switch req.Method {
+			case http.MethodGet:
+				name := strings.TrimPrefix(req.URL.Query().Get("filters"), "name[equals]")
+				body := `{"totalCount": 0, "contents": []}`
+				if id, ok := tags[name]; ok {
+					body = `{"totalCount": 1, "contents": [{"id": "` + id + `", "name": "` + name + `"}]}`
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+			case http.MethodPost:
+				var payload map[string]interface{}
+				if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+					t.Fatalf("Failed to decode request body: %v", err)
+				}
+				name := payload["name"].(string)
+				*created = append(*created, name)
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       io.NopCloser(strings.NewReader(`{"id": "new-` + name + `"}`)),
+				}, nil
+			}
+
+			t.Errorf("Unexpected method %s", req.Method)
+			return nil, nil
+		},
+	}
+}
+
+func TestTagResolver_Resolve(t *testing.T) {
+	created := make([]string, 0)
+	mockClient := newTagsMock(t, map[string]string{"Go": "tag-go"}, &created)
+
+	resolver := NewTagResolver(NewClient("service-id", "test-api-key", "tags", mockClient), "name")
+	ids, err := resolver.Resolve(context.Background(), []string{"Go", "microCMS"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if want := []string{"tag-go", "new-microCMS"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Resolve() = %v, want %v", ids, want)
+	}
+	if want := []string{"microCMS"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("created = %v, want %v", created, want)
+	}
+
+	// 作成したタグは再度作成しない
+	if _, err := resolver.Resolve(context.Background(), []string{"microCMS"}); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(created) != 1 {
+		t.Errorf("created = %v, want 1 tag", created)
+	}
+}
+
+func TestTagResolver_Lookup(t *testing.T) {
+	created := make([]string, 0)
+	mockClient := newTagsMock(t, map[string]string{"Go": "tag-go"}, &created)
+
+	resolver := NewTagResolver(NewClient("service-id", "test-api-key", "tags", mockClient), "name")
+	ids, missing, err := resolver.Lookup(context.Background(), []string{"Go", "microCMS"})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+
+	if want := []string{"tag-go"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Lookup() ids = %v, want %v", ids, want)
+	}
+	if want := []string{"microCMS"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("Lookup() missing = %v, want %v", missing, want)
+	}
+	if len(created) != 0 {
+		t.Errorf("Lookup() must not create tags, created %v", created)
+	}
+}
+
+func TestTagResolver_Error(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(`{"message": "Field 'name' is not found."}`)),
+			}, nil
+		},
+	}
+
+	resolver := NewTagResolver(NewClient("service-id", "test-api-key", "tags", mockClient), "name")
+	_, err := resolver.Resolve(context.Background(), []string{"Go"})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to find tag Go") {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
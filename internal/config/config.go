@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Kdaito/microcms-publish/internal/cms"
+	"github.com/Kdaito/microcms-publish/internal/md"
+	"github.com/ghodss/yaml"
+)
+
+// Config は設定ファイル（YAML）の内容
+type Config struct {
+	// 記事の属性名（title, tags, qiitaId, content またはfront matterのキー）をキー、
+	// MicroCMSのフィールドIDを値とする対応表
+	Fields cms.FieldMapping `json:"fields"`
+	// タグの送信方法
+	Tags Tags `json:"tags"`
+	// 目次の送信方法
+	TOC TOC `json:"toc"`
+	// 概要の抜粋の設定
+	Excerpt Excerpt `json:"excerpt"`
+}
+
+// Tags はタグをMicroCMSに送信する方法の設定
+type Tags struct {
+	// text（カンマ区切りの文字列）、multiSelect（セレクトフィールドの配列）、
+	// reference（タグを管理するAPIのコンテンツへの参照）のいずれか
+	Mode string `json:"mode"`
+	// referenceの場合に、タグを管理するAPIのエンドポイント
+	Endpoint string `json:"endpoint"`
+	// referenceの場合に、タグ名を保持するフィールドID
+	NameField string `json:"nameField"`
+}
+
+// タグの送信方法
+const (
+	TagsModeText        = "text"
+	TagsModeMultiSelect = "multiSelect"
+	TagsModeReference   = "reference"
+)
+
+// TOC は目次をMicroCMSに送信する方法の設定
+type TOC struct {
+	// fieldsのtocに対応させたフィールドの形式。json（見出しを入れ子にしたJSON）または
+	// repeated（見出しを順に並べた繰り返しフィールド）
+	Format string `json:"format"`
+	// repeatedの場合に、見出しを表すカスタムフィールドのID
+	CustomFieldID string `json:"customFieldId"`
+	// 本文の先頭に目次を埋め込む
+	Embed bool `json:"embed"`
+}
+
+// Excerpt は本文から概要を抜粋する方法の設定
+type Excerpt struct {
+	// 抜粋する文字数
+	Length int `json:"length"`
+}
+
+// 目次の形式
+const (
+	TOCFormatJSON     = "json"
+	TOCFormatRepeated = "repeated"
+)
+
+func Default() *Config {
+	return &Config{
+		Fields:  cms.DefaultFieldMapping(),
+		Tags:    defaultTags(),
+		TOC:     TOC{Format: TOCFormatJSON},
+		Excerpt: Excerpt{Length: md.DefaultExcerptLength},
+	}
+}
+
+func defaultTags() Tags {
+	return Tags{
+		Mode:      TagsModeText,
+		Endpoint:  "tags",
+		NameField: "name",
+	}
+}
+
+// Load は設定ファイルを読み込む。pathが空の場合は既定の設定を返す
+func Load(path string) (*Config, error) {
+	if path == "" {
+		return Default(), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("invalid config format: %w", err)
+	}
+
+	if len(config.Fields) == 0 {
+		config.Fields = cms.DefaultFieldMapping()
+	}
+
+	// 指定されていない項目は既定値にする
+	defaults := defaultTags()
+	if config.Tags.Mode == "" {
+		config.Tags.Mode = defaults.Mode
+	}
+	if config.Tags.Endpoint == "" {
+		config.Tags.Endpoint = defaults.Endpoint
+	}
+	if config.Tags.NameField == "" {
+		config.Tags.NameField = defaults.NameField
+	}
+	if config.TOC.Format == "" {
+		config.TOC.Format = TOCFormatJSON
+	}
+	if config.Excerpt.Length == 0 {
+		config.Excerpt.Length = md.DefaultExcerptLength
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func (c *Config) Validate() error {
+	if c.Fields[cms.AttrQiitaID] == "" {
+		return errors.New("fields.qiitaId is required to identify contents")
+	}
+	for attr, fieldID := range c.Fields {
+		if fieldID == "" {
+			return fmt.Errorf("field ID for %s is empty", attr)
+		}
+	}
+	switch c.Tags.Mode {
+	case TagsModeText, TagsModeMultiSelect, TagsModeReference:
+	default:
+		return fmt.Errorf("tags.mode must be %s, %s or %s", TagsModeText, TagsModeMultiSelect, TagsModeReference)
+	}
+	switch c.TOC.Format {
+	case TOCFormatJSON:
+	case TOCFormatRepeated:
+		if c.TOC.CustomFieldID == "" {
+			return errors.New("toc.customFieldId is required when toc.format is repeated")
+		}
+	default:
+		return fmt.Errorf("toc.format must be %s or %s", TOCFormatJSON, TOCFormatRepeated)
+	}
+	if c.Excerpt.Length < 0 {
+		return errors.New("excerpt.length must be positive")
+	}
+	return nil
+}
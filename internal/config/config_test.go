@@ -0,0 +1,159 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/Kdaito/microcms-publish/internal/cms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedConfig *Config
+		expectedError  string
+	}{
+		{
+			name:           "正常系_設定ファイルなし",
+			path:           "",
+			expectedConfig: Default(),
+			expectedError:  "",
+		},
+		{
+			name: "正常系",
+			path: "../../mocks/config/success.yaml",
+			expectedConfig: &Config{
+				Fields: cms.FieldMapping{
+					"title":   "title",
+					"content": "body",
+					"qiitaId": "sourceId",
+					"slug":    "slug",
+				},
+				Tags: Tags{
+					Mode:      TagsModeText,
+					Endpoint:  "tags",
+					NameField: "name",
+				},
+				TOC:     TOC{Format: TOCFormatJSON},
+				Excerpt: Excerpt{Length: 120},
+			},
+			expectedError: "",
+		},
+		{
+			name: "正常系_タグを参照する",
+			path: "../../mocks/config/tagsReference.yaml",
+			expectedConfig: &Config{
+				Fields: cms.FieldMapping{
+					"title":   "title",
+					"tags":    "categories",
+					"qiitaId": "qiitaId",
+					"content": "content",
+				},
+				Tags: Tags{
+					Mode:      TagsModeReference,
+					Endpoint:  "categories",
+					NameField: "name",
+				},
+				TOC:     TOC{Format: TOCFormatJSON},
+				Excerpt: Excerpt{Length: 120},
+			},
+			expectedError: "",
+		},
+		{
+			name:           "異常系_qiitaIdがない",
+			path:           "../../mocks/config/withoutQiitaId.yaml",
+			expectedConfig: nil,
+			expectedError:  "fields.qiitaId is required to identify contents",
+		},
+		{
+			name:           "異常系_タグの送信方法が違う",
+			path:           "../../mocks/config/invalidTagsMode.yaml",
+			expectedConfig: nil,
+			expectedError:  "tags.mode must be text, multiSelect or reference",
+		},
+		{
+			name: "正常系_目次を繰り返しフィールドで送信する",
+			path: "../../mocks/config/tocRepeated.yaml",
+			expectedConfig: &Config{
+				Fields: cms.FieldMapping{
+					"title":   "title",
+					"qiitaId": "qiitaId",
+					"content": "content",
+					"toc":     "toc",
+				},
+				Tags: Tags{
+					Mode:      TagsModeText,
+					Endpoint:  "tags",
+					NameField: "name",
+				},
+				TOC: TOC{
+					Format:        TOCFormatRepeated,
+					CustomFieldID: "heading",
+					Embed:         true,
+				},
+				Excerpt: Excerpt{Length: 120},
+			},
+			expectedError: "",
+		},
+		{
+			name: "正常系_抜粋の文字数",
+			path: "../../mocks/config/excerpt.yaml",
+			expectedConfig: &Config{
+				Fields: cms.FieldMapping{
+					"qiitaId": "qiitaId",
+					"excerpt": "description",
+				},
+				Tags: Tags{
+					Mode:      TagsModeText,
+					Endpoint:  "tags",
+					NameField: "name",
+				},
+				TOC:     TOC{Format: TOCFormatJSON},
+				Excerpt: Excerpt{Length: 80},
+			},
+			expectedError: "",
+		},
+		{
+			name:           "異常系_抜粋の文字数が負",
+			path:           "../../mocks/config/invalidExcerptLength.yaml",
+			expectedConfig: nil,
+			expectedError:  "excerpt.length must be positive",
+		},
+		{
+			name:           "異常系_繰り返しフィールドのIDがない",
+			path:           "../../mocks/config/tocWithoutCustomFieldId.yaml",
+			expectedConfig: nil,
+			expectedError:  "toc.customFieldId is required when toc.format is repeated",
+		},
+		{
+			name:           "異常系_フォーマットが違う",
+			path:           "../../mocks/config/invalidFormat.yaml",
+			expectedConfig: nil,
+			expectedError:  "invalid config format",
+		},
+		{
+			name:           "異常系_ファイルがない",
+			path:           "../../mocks/config/notFound.yaml",
+			expectedConfig: nil,
+			expectedError:  "failed to read config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			config, err := Load(tt.path)
+
+			// then
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedConfig, config)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrNotCommitted はファイルがまだコミットされていないことを表す
+var ErrNotCommitted = errors.New("file is not committed")
+
+// FirstCommitTime はファイルが最初にコミットされた日時を返す（リネームされていても追跡する）
+//
+// 浅いクローンでは正しい日時を取得できないため、`fetch-depth: 0` でチェックアウトすること
+func FirstCommitTime(dir, file string) (time.Time, error) {
+	cmd := exec.Command("git", "log", "--follow", "--diff-filter=A", "--format=%aI", "--", file)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to run git log: %w", err)
+	}
+
+	// 新しい順に出力されるため、最後の行が最初のコミット
+	lines := strings.Fields(string(out))
+	if len(lines) == 0 {
+		return time.Time{}, ErrNotCommitted
+	}
+
+	t, err := time.Parse(time.RFC3339, lines[len(lines)-1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid commit date: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// テスト用のリポジトリでコミットする
+func commit(t *testing.T, dir, date string, args ...string) {
+	t.Helper()
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com", "GIT_COMMITTER_DATE="+date,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	run(args...)
+	run("commit", "-q", "-m", "commit")
+}
+
+func TestFirstCommitTime(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// given
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git init failed: %v", err)
+	}
+
+	write := func(name, content string) {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("public/a.md", "first")
+	commit(t, dir, "2024-01-02T03:04:05+09:00", "add", ".")
+	write("public/a.md", "second")
+	commit(t, dir, "2024-06-01T00:00:00+09:00", "add", ".")
+	commit(t, dir, "2024-07-01T00:00:00+09:00", "mv", "public/a.md", "public/b.md")
+	write("public/c.md", "uncommitted")
+
+	tests := []struct {
+		name          string
+		file          string
+		expected      time.Time
+		expectedError error
+	}{
+		{
+			name:     "正常系_リネームされたファイル",
+			file:     "public/b.md",
+			expected: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 9*60*60)),
+		},
+		{
+			name:          "異常系_コミットされていない",
+			file:          "public/c.md",
+			expectedError: ErrNotCommitted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			result, err := FirstCommitTime(dir, tt.file)
+
+			// then
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, tt.expected.Equal(result), "expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package md
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+var mathLanguage = []byte("math")
+
+type codeBlockRenderer struct {
+	writer html.Writer
+}
+
+func (r *codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+}
+
+func (r *codeBlockRenderer) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.FencedCodeBlock)
+	language, filename := splitCodeInfo(n, source)
+
+	// ```math は数式として出力する
+	if bytes.Equal(language, mathLanguage) {
+		if entering {
+			writeDisplayMath(w, source, n)
+		}
+		return ast.WalkSkipChildren, nil
+	}
+
+	if entering {
+		// ファイル名があればQiitaと同様にコードの上に表示する
+		if len(filename) > 0 {
+			_, _ = w.WriteString("<div class=\"code-frame\" data-filename=\"")
+			_, _ = w.Write(util.EscapeHTML(filename))
+			_, _ = w.WriteString("\">\n<div class=\"code-filename\">")
+			_, _ = w.Write(util.EscapeHTML(filename))
+			_, _ = w.WriteString("</div>\n")
+		}
+
+		_, _ = w.WriteString("<pre><code")
+		if len(language) > 0 {
+			_, _ = w.WriteString(" class=\"language-")
+			r.writer.Write(w, language)
+			_ = w.WriteByte('"')
+		}
+		_ = w.WriteByte('>')
+
+		l := n.Lines().Len()
+		for i := 0; i < l; i++ {
+			line := n.Lines().At(i)
+			r.writer.RawWrite(w, line.Value(source))
+		}
+	} else {
+		_, _ = w.WriteString("</code></pre>\n")
+		if len(filename) > 0 {
+			_, _ = w.WriteString("</div>\n")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+// splitCodeInfo はQiitaの ```lang:filename 形式の情報文字列を言語とファイル名に分割する
+func splitCodeInfo(n *ast.FencedCodeBlock, source []byte) ([]byte, []byte) {
+	if n.Info == nil {
+		return nil, nil
+	}
+
+	info := util.TrimRightSpace(util.TrimLeftSpace(n.Info.Segment.Value(source)))
+	i := bytes.IndexByte(info, ':')
+	if i < 0 {
+		return n.Language(source), nil
+	}
+
+	language := info[:i]
+	if j := bytes.IndexAny(language, " \t"); j >= 0 {
+		language = language[:j]
+	}
+	filename := util.TrimLeftSpace(info[i+1:])
+	return language, filename
+}
+
+type codeBlockExtension struct{}
+
+// CodeBlockExtension はQiitaの ```lang:filename 記法に対応したコードブロックを出力する
+var CodeBlockExtension = &codeBlockExtension{}
+
+func (e *codeBlockExtension) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&codeBlockRenderer{writer: html.DefaultWriter}, 500),
+	))
+}
@@ -0,0 +1,39 @@
+package md
+
+import "fmt"
+
+// ParseStage は記事情報の取得に失敗した段階
+type ParseStage string
+
+const (
+	// ファイルの読み込み
+	StageRead ParseStage = "read"
+	// front matterの区切りの検出
+	StageFrontMatter ParseStage = "frontMatter"
+	// front matterの値の読み込みと検証
+	StageMetadata ParseStage = "metadata"
+	// 本文のHTMLへの変換
+	StageRender ParseStage = "render"
+	// 本文で参照している画像の解決
+	StageImages ParseStage = "images"
+)
+
+// ParseError は記事情報を取得できなかったファイルと、その原因
+type ParseError struct {
+	// ワークスペースからのファイルパス
+	Path  string
+	Stage ParseStage
+	Err   error
+}
+
+func newParseError(path string, stage ParseStage, err error) *ParseError {
+	return &ParseError{Path: path, Stage: stage, Err: err}
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Path, e.Stage, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,45 @@
+package md
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// DefaultExcerptLength は抜粋の既定の文字数
+const DefaultExcerptLength = 120
+
+// excerptEllipsis は抜粋を途中で切った場合に末尾に付ける
+const excerptEllipsis = "…"
+
+// excerpt は本文の段落のテキストから、先頭のlength文字（日本語も1文字として数える）を抜粋する
+// 見出し・コード・数式のブロック、表、画像、HTMLは含めない
+func excerpt(doc ast.Node, source []byte, length int) string {
+	paragraphs := make([]string, 0)
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node.Kind() {
+		case ast.KindHeading:
+			return ast.WalkSkipChildren, nil
+		case ast.KindParagraph, ast.KindTextBlock:
+			if text := strings.Join(strings.Fields(plainText(node, source)), " "); text != "" {
+				paragraphs = append(paragraphs, text)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return truncate(strings.Join(paragraphs, " "), length)
+}
+
+// truncate は文字数がlengthを超える場合に、length文字に切り詰めて省略記号を付ける
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:length])) + excerptEllipsis
+}
@@ -0,0 +1,118 @@
+package md
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	utf8BOM              = []byte("\xef\xbb\xbf")
+	frontMatterDelimiter = []byte("---")
+)
+
+var errInvalidFrontMatter = errors.New("invalid front matter format")
+
+// frontMatter は記事ファイルの先頭の `---` で囲まれたYAMLと、それ以降の本文
+type frontMatter struct {
+	// 開始の `---` からfront matterの最後の行まで
+	// YAMLのエラーの行番号がファイルの行番号と一致するよう、開始の `---`（YAMLの文書の開始）を含める
+	document []byte
+	body     []byte
+}
+
+// extractFrontMatter はファイルの内容をfront matterと本文に分ける
+//
+// 1行目が `---` の場合のみfront matterとして扱う。BOMとCRLFの改行は取り除き、
+// 終わりの `---` の後に改行がない（本文がない）ファイルも扱える
+func extractFrontMatter(content []byte) (*frontMatter, error) {
+	content = bytes.TrimPrefix(content, utf8BOM)
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if !isFrontMatterDelimiter(lines[0]) {
+		return nil, fmt.Errorf("%w: the first line must be ---", errInvalidFrontMatter)
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if isFrontMatterDelimiter(lines[i]) {
+			return &frontMatter{
+				document: bytes.Join(lines[:i], nil),
+				body:     bytes.Join(lines[i+1:], nil),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: closing --- is not found", errInvalidFrontMatter)
+}
+
+func isFrontMatterDelimiter(line []byte) bool {
+	return bytes.Equal(bytes.TrimRight(line, " \t\n"), frontMatterDelimiter)
+}
+
+// decode はfront matterを読み込む。エラーにはファイルの行番号を含める
+func (f *frontMatter) decode(out interface{}) error {
+	if err := yaml.Unmarshal(f.document, out); err != nil {
+		return fmt.Errorf("invalid metadata format: %s", yamlErrorMessage(err))
+	}
+	return nil
+}
+
+// decodeFields はfront matterを、MicroCMSに送信できるようJSONと同じ型の値で読み込む
+func (f *frontMatter) decodeFields() (map[string]interface{}, error) {
+	var fields map[string]interface{}
+	if err := f.decode(&fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		fields[key] = jsonValue(value)
+	}
+	return fields, nil
+}
+
+// jsonValue はYAMLの値を、JSONを読み込んだ場合と同じ型に変換する
+// 数値はfloat64、日時は文字列（日付だけの場合は `2006-01-02` の形式）にする
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = jsonValue(child)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			m[fmt.Sprint(key)] = jsonValue(child)
+		}
+		return m
+	case []interface{}:
+		for i, child := range v {
+			v[i] = jsonValue(child)
+		}
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case time.Time:
+		if v.Equal(v.Truncate(24*time.Hour)) && v.Location() == time.UTC {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+// yamlErrorMessage はYAMLのエラーを `line N: ...` の形式で1行にまとめる
+func yamlErrorMessage(err error) string {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		return strings.Join(typeErr.Errors, "; ")
+	}
+	return strings.TrimPrefix(err.Error(), "yaml: ")
+}
@@ -0,0 +1,129 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFrontMatter(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		expectedDocument string
+		expectedBody     string
+		expectedError    string
+	}{
+		{
+			name:             "正常系",
+			content:          "---\ntitle: タイトル\n---\n## 本文\n",
+			expectedDocument: "---\ntitle: タイトル\n",
+			expectedBody:     "## 本文\n",
+		},
+		{
+			name:             "正常系_CRLF",
+			content:          "---\r\ntitle: タイトル\r\n---\r\n## 本文\r\n",
+			expectedDocument: "---\ntitle: タイトル\n",
+			expectedBody:     "## 本文\n",
+		},
+		{
+			name:             "正常系_BOM",
+			content:          "\xef\xbb\xbf---\ntitle: タイトル\n---\n## 本文\n",
+			expectedDocument: "---\ntitle: タイトル\n",
+			expectedBody:     "## 本文\n",
+		},
+		{
+			name:             "正常系_終わりの区切りの後に改行がない",
+			content:          "---\ntitle: タイトル\n---",
+			expectedDocument: "---\ntitle: タイトル\n",
+			expectedBody:     "",
+		},
+		{
+			name:             "正常系_本文の区切り線",
+			content:          "---\ntitle: タイトル\n---\n本文\n\n---\n\n続き\n",
+			expectedDocument: "---\ntitle: タイトル\n",
+			expectedBody:     "本文\n\n---\n\n続き\n",
+		},
+		{
+			name:          "異常系_1行目が区切りでない",
+			content:       "\n---\ntitle: タイトル\n---\n本文\n",
+			expectedError: "invalid front matter format: the first line must be ---",
+		},
+		{
+			name:          "異常系_終わりの区切りがない",
+			content:       "---\ntitle: タイトル\n本文\n",
+			expectedError: "invalid front matter format: closing --- is not found",
+		},
+		{
+			name:          "異常系_空のファイル",
+			content:       "",
+			expectedError: "invalid front matter format: the first line must be ---",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			fm, err := extractFrontMatter([]byte(tt.content))
+
+			// then
+			if tt.expectedError != "" {
+				assert.Nil(t, fm)
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedDocument, string(fm.document))
+				assert.Equal(t, tt.expectedBody, string(fm.body))
+			}
+		})
+	}
+}
+
+func TestFrontMatter_DecodeFields(t *testing.T) {
+	tests := []struct {
+		name           string
+		document       string
+		expectedFields map[string]interface{}
+		expectedError  string
+	}{
+		{
+			name:     "正常系_JSONと同じ型で読み込む",
+			document: "---\ntitle: タイトル\ncount: 3\nratio: 0.5\ndate: 2025-03-23\nupdated: 2025-03-23T20:50:41+09:00\nquoted: '2025-03-23'\nid: null\nmicrocms:\n  status: draft\ntags:\n  - Go\n  - 1\n",
+			expectedFields: map[string]interface{}{
+				"title":    "タイトル",
+				"count":    float64(3),
+				"ratio":    0.5,
+				"date":     "2025-03-23",
+				"updated":  "2025-03-23T20:50:41+09:00",
+				"quoted":   "2025-03-23",
+				"id":       nil,
+				"microcms": map[string]interface{}{"status": "draft"},
+				"tags":     []interface{}{"Go", float64(1)},
+			},
+		},
+		{
+			name:          "異常系_invalidYaml",
+			document:      "---\ntitle: タイトル: 不正\n",
+			expectedError: "invalid metadata format: line 2: mapping values are not allowed in this context",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			fm := &frontMatter{document: []byte(tt.document)}
+
+			// when
+			fields, err := fm.decodeFields()
+
+			// then
+			if tt.expectedError != "" {
+				assert.Nil(t, fields)
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedFields, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package md
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 目次からリンクできるよう、見出しにIDを付与する
+// IDはGitHubと同様に、見出しのテキストを小文字にして記号を除き、空白を `-` にしたもの
+// 日本語などの文字はそのまま残す
+//
+//	## Go の基本 -> <h2 id="go-の基本">Go の基本</h2>
+
+// Heading は目次の見出し
+type Heading struct {
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+	Level int    `json:"level"`
+	// 直後に続く、より深いレベルの見出し
+	Children []*Heading `json:"children,omitempty"`
+}
+
+type headingIDTransformer struct{}
+
+func (t *headingIDTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	slugger := newSlugger()
+	source := reader.Source()
+
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || node.Kind() != ast.KindHeading {
+			return ast.WalkContinue, nil
+		}
+		node.SetAttributeString("id", []byte(slugger.slug(plainText(node, source))))
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+type headingExtension struct{}
+
+// HeadingExtension は見出しにIDを付与するgoldmarkの拡張
+var HeadingExtension = &headingExtension{}
+
+func (e *headingExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&headingIDTransformer{}, 100),
+	))
+}
+
+// slugger は見出しのテキストから、記事の中で重複しないIDを生成する
+type slugger struct {
+	// 生成したIDと、重複した回数
+	seen map[string]int
+}
+
+func newSlugger() *slugger {
+	return &slugger{seen: make(map[string]int)}
+}
+
+func (s *slugger) slug(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(value)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r), unicode.IsMark(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('-')
+		}
+	}
+
+	slug := b.String()
+	if slug == "" {
+		slug = "heading"
+	}
+
+	// 同じIDがすでにある場合は、末尾に連番を付ける
+	count, ok := s.seen[slug]
+	if !ok {
+		s.seen[slug] = 0
+		return slug
+	}
+	for {
+		count++
+		candidate := fmt.Sprintf("%s-%d", slug, count)
+		if _, ok := s.seen[candidate]; !ok {
+			s.seen[slug] = count
+			s.seen[candidate] = 0
+			return candidate
+		}
+	}
+}
+
+// plainText はノードに含まれるテキストを、Markdownの記法を除いて連結する
+func plainText(node ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			buf.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(n.Value)
+		case *Math:
+			buf.Write(n.Value.Value(source))
+		case *ast.Image:
+			// 代替テキストは本文として扱わない
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+// collectHeadings は記事の見出しを、レベルに応じて入れ子にして返す
+// noteや引用の中の見出しは目次に含めない
+func collectHeadings(doc ast.Node, source []byte) []*Heading {
+	headings := make([]*Heading, 0)
+	// 現在の見出しと、その親の見出し
+	parents := make([]*Heading, 0)
+
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		h, ok := node.(*ast.Heading)
+		if !ok {
+			continue
+		}
+
+		heading := &Heading{Text: plainText(h, source), Level: h.Level}
+		if id, ok := h.AttributeString("id"); ok {
+			heading.ID = string(id.([]byte))
+		}
+
+		for len(parents) > 0 && parents[len(parents)-1].Level >= heading.Level {
+			parents = parents[:len(parents)-1]
+		}
+		if len(parents) == 0 {
+			headings = append(headings, heading)
+		} else {
+			parent := parents[len(parents)-1]
+			parent.Children = append(parent.Children, heading)
+		}
+		parents = append(parents, heading)
+	}
+
+	return headings
+}
+
+// RenderTOC は目次を、見出しへのリンクのリストとして出力する。見出しがない場合は空文字を返す
+func RenderTOC(headings []*Heading) string {
+	if len(headings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<nav class=\"toc\">\n")
+	writeTOCList(&b, headings)
+	b.WriteString("</nav>\n")
+	return b.String()
+}
+
+func writeTOCList(b *strings.Builder, headings []*Heading) {
+	b.WriteString("<ul>\n")
+	for _, heading := range headings {
+		fmt.Fprintf(b, "<li><a href=\"#%s\">%s</a>", html.EscapeString(heading.ID), html.EscapeString(heading.Text))
+		if len(heading.Children) > 0 {
+			b.WriteString("\n")
+			writeTOCList(b, heading.Children)
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
+}
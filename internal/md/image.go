@@ -0,0 +1,121 @@
+package md
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// Image は記事中で参照されている画像
+type Image struct {
+	// 記事中に書かれている画像のURLまたはパス
+	Src string
+	// リポジトリ内の画像のファイルパス（外部URLの場合は空）
+	Path string
+}
+
+func (i Image) IsLocal() bool {
+	return i.Path != ""
+}
+
+// collectImages は記事中の画像を重複なく収集する
+//
+// 相対パスは記事ファイルのディレクトリから、`/` 始まりのパスはワークスペースから解決する
+// リポジトリ外のファイルをアップロードしないよう、ワークスペースの外を指すパスはエラーにする
+func collectImages(doc ast.Node, source []byte, workspace, filePath string) ([]Image, error) {
+	images := make([]Image, 0)
+	seen := make(map[string]bool)
+
+	err := ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || node.Kind() != ast.KindImage {
+			return ast.WalkContinue, nil
+		}
+
+		src := string(node.(*ast.Image).Destination)
+		if src == "" || seen[src] {
+			return ast.WalkContinue, nil
+		}
+		seen[src] = true
+
+		image := Image{Src: src}
+		if isLocalSrc(src) {
+			if strings.HasPrefix(src, "/") {
+				image.Path = filepath.Join(workspace, src)
+			} else {
+				image.Path = filepath.Join(filepath.Dir(filePath), src)
+			}
+			if !isInside(workspace, image.Path) {
+				return ast.WalkStop, fmt.Errorf("image %s is outside the workspace", src)
+			}
+		}
+		images = append(images, image)
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// isInside はpathがdirの中にあるかを返す。シンボリックリンクは解決してから判定する
+func isInside(dir, path string) bool {
+	dir, err := resolvePath(dir)
+	if err != nil {
+		return false
+	}
+	path, err = resolvePath(path)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvePath は絶対パスに変換し、ファイルが存在する場合はシンボリックリンクを解決する
+func resolvePath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved, nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	return path, nil
+}
+
+func isLocalSrc(src string) bool {
+	if strings.HasPrefix(src, "//") {
+		return false
+	}
+	u, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == ""
+}
+
+// ReplaceImageSources は記事本文の画像のsrcを、Srcをキーとしたurlsの値に置き換える
+func (i *Item) ReplaceImageSources(urls map[string]string) {
+	for src, newSrc := range urls {
+		oldAttr := `src="` + string(imageSrcAttr(src)) + `"`
+		newAttr := `src="` + string(imageSrcAttr(newSrc)) + `"`
+		i.Content = strings.ReplaceAll(i.Content, oldAttr, newAttr)
+	}
+}
+
+// goldmarkの画像のレンダリングと同じエスケープを行う
+func imageSrcAttr(src string) []byte {
+	return util.EscapeHTML(util.URLEscape([]byte(src), true))
+}
@@ -0,0 +1,239 @@
+package md
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 数式はKaTeXでクライアント側から描画できるよう、TeXのまま出力する
+//
+//	$...$              -> <span class="math inline">...</span>
+//	$$...$$ / ```math  -> <div class="math display">...</div>
+var mathDelimiter = []byte("$$")
+
+var (
+	KindMath      = ast.NewNodeKind("Math")
+	KindMathBlock = ast.NewNodeKind("MathBlock")
+)
+
+// Math は `$...$` で囲まれたインライン数式を表すノード
+type Math struct {
+	ast.BaseInline
+	Value text.Segment
+}
+
+func (n *Math) Kind() ast.NodeKind {
+	return KindMath
+}
+
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Value": string(n.Value.Value(source))}, nil)
+}
+
+// MathBlock は `$$` で囲まれたディスプレイ数式を表すノード
+type MathBlock struct {
+	ast.BaseBlock
+	closed bool
+}
+
+func (n *MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type mathInlineParser struct{}
+
+func (p *mathInlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+
+	// 閉じられていない `$$` の2文字目から数式として読まない
+	if block.PrecendingCharacter() == '$' {
+		return nil
+	}
+	// 段落の中の `$$...$$` はインライン数式として扱う
+	if len(line) > 1 && line[1] == '$' {
+		return parseDoubleDollarMath(block, line, segment)
+	}
+	if len(line) < 3 || util.IsSpace(line[1]) {
+		return nil
+	}
+
+	// `$5 と $10` のような通貨表記を数式と誤認しないよう、
+	// 閉じ記号の直前が空白でなく、直後が数字でないものに限る
+	for i := 2; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '$':
+			if util.IsSpace(line[i-1]) {
+				continue
+			}
+			if i+1 < len(line) && util.IsNumeric(line[i+1]) {
+				continue
+			}
+			node := &Math{Value: text.NewSegment(segment.Start+1, segment.Start+i)}
+			block.Advance(i + 1)
+			return node
+		}
+	}
+	return nil
+}
+
+func parseDoubleDollarMath(block text.Reader, line []byte, segment text.Segment) ast.Node {
+	for i := 2; i+1 < len(line); i++ {
+		switch {
+		case line[i] == '\\':
+			i++
+		case line[i] == '$' && line[i+1] == '$':
+			if i == 2 {
+				return nil
+			}
+			node := &Math{Value: text.NewSegment(segment.Start+2, segment.Start+i)}
+			block.Advance(i + 2)
+			return node
+		}
+	}
+	return nil
+}
+
+type mathBlockParser struct{}
+
+func (b *mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (b *mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], mathDelimiter) {
+		return nil, parser.NoChildren
+	}
+
+	node := &MathBlock{}
+	start := segment.Start + pos + len(mathDelimiter)
+	rest := util.TrimRightSpace(line[pos+len(mathDelimiter):])
+
+	// `$$` だけの行は、次の行からの数式を開始する
+	// `$$ x $$` のように1行で閉じている場合のみ、その行をディスプレイ数式にする
+	// `$$x$$ の後に文章が続く` 行は段落として、インライン数式に任せる
+	if !util.IsBlank(rest) {
+		end := bytes.Index(rest, mathDelimiter)
+		if end < 0 || !util.IsBlank(rest[end+len(mathDelimiter):]) {
+			return nil, parser.NoChildren
+		}
+		value := rest[:end]
+		if !util.IsBlank(value) {
+			left, right := util.TrimLeftSpaceLength(value), util.TrimRightSpaceLength(value)
+			node.Lines().Append(text.NewSegment(start+left, start+len(value)-right))
+		}
+		node.closed = true
+	}
+
+	reader.Advance(segment.Len() - newlineLength(line))
+	return node, parser.NoChildren
+}
+
+func (b *mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	n := node.(*MathBlock)
+	if n.closed {
+		return parser.Close
+	}
+
+	line, segment := reader.PeekLine()
+	if line == nil {
+		return parser.Close
+	}
+
+	trimmed := util.TrimRightSpace(line)
+	if bytes.HasSuffix(trimmed, mathDelimiter) {
+		value := trimmed[:len(trimmed)-len(mathDelimiter)]
+		if !util.IsBlank(value) {
+			node.Lines().Append(text.NewSegment(segment.Start, segment.Start+len(value)))
+		}
+		reader.Advance(segment.Len() - newlineLength(line))
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - newlineLength(line))
+	return parser.Continue | parser.NoChildren
+}
+
+func (b *mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+	// nothing to do
+}
+
+func (b *mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type mathRenderer struct{}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.renderMath)
+	reg.Register(KindMathBlock, r.renderMathBlock)
+}
+
+func (r *mathRenderer) renderMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*Math)
+		_, _ = w.WriteString("<span class=\"math inline\">")
+		_, _ = w.Write(util.EscapeHTML(n.Value.Value(source)))
+		_, _ = w.WriteString("</span>")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		writeDisplayMath(w, source, node)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// ```math のコードブロックと共通の出力
+func writeDisplayMath(w util.BufWriter, source []byte, node ast.Node) {
+	_, _ = w.WriteString("<div class=\"math display\">")
+	l := node.Lines().Len()
+	for i := 0; i < l; i++ {
+		line := node.Lines().At(i)
+		_, _ = w.Write(util.EscapeHTML(line.Value(source)))
+	}
+	_, _ = w.WriteString("</div>\n")
+}
+
+type mathExtension struct{}
+
+// MathExtension はQiitaの数式記法（`$...$`、`$$...$$`、```math）を保護して出力する
+var MathExtension = &mathExtension{}
+
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&mathBlockParser{}, 760)),
+		parser.WithInlineParsers(util.Prioritized(&mathInlineParser{}, 150)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&mathRenderer{}, 500),
+	))
+}
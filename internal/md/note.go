@@ -0,0 +1,147 @@
+package md
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Qiitaの `:::note <kind>` ~ `:::` 記法
+var (
+	noteOpener = []byte(":::note")
+	noteCloser = []byte(":::")
+)
+
+// Qiitaで使用できるnoteの種類（省略時はinfo）
+var noteKinds = map[string]bool{
+	"info":  true,
+	"warn":  true,
+	"alert": true,
+}
+
+const defaultNoteKind = "info"
+
+var KindNote = ast.NewNodeKind("Note")
+
+// Note は `:::note` で囲まれたブロックを表すノード
+type Note struct {
+	ast.BaseBlock
+	NoteKind string
+}
+
+func (n *Note) Kind() ast.NodeKind {
+	return KindNote
+}
+
+func (n *Note) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"NoteKind": n.NoteKind}, nil)
+}
+
+type noteParser struct{}
+
+func (b *noteParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (b *noteParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], noteOpener) {
+		return nil, parser.NoChildren
+	}
+
+	// `:::notes` のような記法は対象外
+	rest := line[pos+len(noteOpener):]
+	if len(rest) > 0 && !util.IsSpace(rest[0]) {
+		return nil, parser.NoChildren
+	}
+
+	kind := string(util.TrimRightSpace(util.TrimLeftSpace(rest)))
+	if !noteKinds[kind] {
+		kind = defaultNoteKind
+	}
+
+	reader.Advance(segment.Len() - newlineLength(line))
+	return &Note{NoteKind: kind}, parser.HasChildren
+}
+
+func (b *noteParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if bytes.Equal(util.TrimRightSpace(util.TrimLeftSpace(line)), noteCloser) && !inOpenCodeBlock(node, pc) {
+		reader.Advance(segment.Len() - newlineLength(line))
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+// inOpenCodeBlock はnoteの中（リストの中などを含む）に、まだ閉じていないコードブロックがあるかを返す
+// コードブロックの中の `:::` はnoteの終わりとして扱わない
+func inOpenCodeBlock(node ast.Node, pc parser.Context) bool {
+	inNote := false
+	for _, block := range pc.OpenedBlocks() {
+		if block.Node == node {
+			inNote = true
+			continue
+		}
+		if inNote && (block.Node.Kind() == ast.KindFencedCodeBlock || block.Node.Kind() == KindMathBlock) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *noteParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+	// nothing to do
+}
+
+func (b *noteParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *noteParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type noteRenderer struct{}
+
+func (r *noteRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindNote, r.renderNote)
+}
+
+func (r *noteRenderer) renderNote(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*Note)
+		fmt.Fprintf(w, "<div class=\"note %s\">\n", n.NoteKind)
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+type noteExtension struct{}
+
+// NoteExtension はQiitaの `:::note` 記法を `<div class="note info">` などに変換する
+var NoteExtension = &noteExtension{}
+
+func (e *noteExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(&noteParser{}, 750),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&noteRenderer{}, 500),
+	))
+}
+
+// 行末の改行は次のブロックの判定に必要なので残す
+func newlineLength(line []byte) int {
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		return 1
+	}
+	return 0
+}
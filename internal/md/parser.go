@@ -7,33 +7,104 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
-	"github.com/ghodss/yaml"
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
 )
 
 type QiitaItemMetadata struct {
-	Title string   `yaml:"title"`
-	Tags  []string `yaml:"tags"`
-	Id    string   `yaml:"id"`
+	Title         string   `yaml:"title"`
+	Tags          []string `yaml:"tags"`
+	Id            string   `yaml:"id"`
+	Private       bool     `yaml:"private"`
+	IgnorePublish bool     `yaml:"ignorePublish"`
+	UpdatedAt     string   `yaml:"updated_at"`
+	// 記事の概要（指定されていない場合は本文から抜粋する）
+	Description string `yaml:"description"`
+
+	Microcms MicrocmsMetadata `yaml:"microcms"`
+}
+
+// MicrocmsMetadata はfront matterの `microcms` に書かれたMicroCMS向けの設定
+type MicrocmsMetadata struct {
+	Status string `yaml:"status"`
 }
 
+// MicroCMSでの公開状態
+const (
+	StatusPublish = "publish"
+	StatusDraft   = "draft"
+)
+
 type Item struct {
-	Title   string `json:"title"`
-	Tags    string `json:"tags"`
-	QiitaID string `json:"qiitaId"`
-	Content string `json:"content"`
+	Title   string  `json:"title"`
+	Tags    string  `json:"tags"`
+	QiitaID string  `json:"qiitaId"`
+	Content string  `json:"content"`
+	Path    string  `json:"-"`
+	Images  []Image `json:"-"`
+
+	// タグの一覧（Tagsはカンマ区切りにしたもの）
+	TagNames []string `json:"-"`
+	// 目次
+	Headings []*Heading `json:"-"`
+	// 概要（front matterのdescription、または本文の先頭の抜粋）
+	Excerpt string `json:"-"`
+
+	// Qiitaの限定共有記事
+	Private bool `json:"-"`
+	// Qiitaに投稿しない記事
+	IgnorePublish bool `json:"-"`
+	// MicroCMSでの公開状態（front matterで指定されていない場合は空）
+	Status string `json:"-"`
+
+	// Qiitaでの更新日時（新規投稿時はゼロ値）
+	UpdatedAt time.Time `json:"-"`
+	// 最初に公開された日時（コマンドがgitの履歴から設定する）
+	PublishedAt time.Time `json:"-"`
+
+	FrontMatter map[string]interface{} `json:"-"`
 }
 
 type Parser struct {
-	workspace string
+	workspace     string
+	excerptLength int
+	// idのない記事を許可する
+	allowMissingID bool
+}
+
+// ParserOption はParserの設定を変更する
+type ParserOption func(*Parser)
+
+// WithExcerptLength は本文から抜粋する文字数を指定する
+func WithExcerptLength(length int) ParserOption {
+	return func(p *Parser) {
+		if length > 0 {
+			p.excerptLength = length
+		}
+	}
+}
+
+// AllowMissingID はidのない記事をエラーにしない
+// Qiitaに投稿する前の記事（プルリクエストで追加された記事など）の計画に使う
+func AllowMissingID() ParserOption {
+	return func(p *Parser) {
+		p.allowMissingID = true
+	}
 }
 
-func NewParser(workspace string) *Parser {
-	return &Parser{
-		workspace: workspace,
+func NewParser(workspace string, opts ...ParserOption) *Parser {
+	p := &Parser{
+		workspace:     workspace,
+		excerptLength: DefaultExcerptLength,
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
 func (s *Parser) parseFromQiitaItem(file string) (*Item, error) {
@@ -43,58 +114,144 @@ func (s *Parser) parseFromQiitaItem(file string) (*Item, error) {
 
 	// ファイルの内容を取得する
 	content, err := os.ReadFile(filePath)
-
 	if err != nil {
-		panic(err)
+		return nil, newParseError(file, StageRead, err)
 	}
 
-	parts := strings.SplitN(string(content), "---\n", 3)
-	if len(parts) < 3 {
-		return nil, errors.New("invalid front matter format")
+	fm, err := extractFrontMatter(content)
+	if err != nil {
+		return nil, newParseError(file, StageFrontMatter, err)
 	}
 
 	var qiitaItemMetadata QiitaItemMetadata
-	if err := yaml.Unmarshal([]byte(parts[1]), &qiitaItemMetadata); err != nil {
-		return nil, errors.New("invalid metadata format")
+	if err := fm.decode(&qiitaItemMetadata); err != nil {
+		return nil, newParseError(file, StageMetadata, err)
+	}
+
+	// Qiitaに投稿しない記事は反映しないため、本文を変換せずに返す
+	// qiita-cliで作成した下書きはidがnullのため、idを必須にしない
+	if qiitaItemMetadata.IgnorePublish {
+		return &Item{
+			Title:         qiitaItemMetadata.Title,
+			Tags:          strings.Join(qiitaItemMetadata.Tags, ","),
+			QiitaID:       qiitaItemMetadata.Id,
+			Path:          file,
+			TagNames:      qiitaItemMetadata.Tags,
+			Private:       qiitaItemMetadata.Private,
+			IgnorePublish: true,
+		}, nil
+	}
+
+	if qiitaItemMetadata.Title == "" || (qiitaItemMetadata.Id == "" && !s.allowMissingID) {
+		return nil, newParseError(file, StageMetadata, errors.New("title or id is empty"))
 	}
 
-	if qiitaItemMetadata.Title == "" || qiitaItemMetadata.Id == "" {
-		return nil, errors.New("title or id is empty")
+	var updatedAt time.Time
+	if qiitaItemMetadata.UpdatedAt != "" {
+		updatedAt, err = time.Parse(time.RFC3339, qiitaItemMetadata.UpdatedAt)
+		if err != nil {
+			return nil, newParseError(file, StageMetadata, errors.New("invalid updated_at format"))
+		}
+	}
+
+	status := qiitaItemMetadata.Microcms.Status
+	if status != "" && status != StatusPublish && status != StatusDraft {
+		return nil, newParseError(file, StageMetadata, fmt.Errorf("microcms.status must be %s or %s", StatusPublish, StatusDraft))
+	}
+
+	// 設定ファイルで任意のキーをMicroCMSのフィールドに対応させられるよう、front matterをそのまま保持する
+	frontMatter, err := fm.decodeFields()
+	if err != nil {
+		return nil, newParseError(file, StageMetadata, err)
+	}
+
+	md := newMarkdown()
+	source := fm.body
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	htmlContent, err := renderHtml(md, source, doc)
+	if err != nil {
+		return nil, newParseError(file, StageRender, err)
+	}
+	images, err := collectImages(doc, source, s.workspace, filePath)
+	if err != nil {
+		return nil, newParseError(file, StageImages, err)
 	}
 
-	htmlContent := parseHtml(parts[2])
 	item := &Item{
 		Title:   qiitaItemMetadata.Title,
 		Tags:    strings.Join(qiitaItemMetadata.Tags, ","),
 		QiitaID: qiitaItemMetadata.Id,
 		Content: htmlContent,
+		Path:    file,
+
+		TagNames: qiitaItemMetadata.Tags,
+		Headings: collectHeadings(doc, source),
+		Excerpt:  strings.TrimSpace(qiitaItemMetadata.Description),
+
+		Private:       qiitaItemMetadata.Private,
+		IgnorePublish: qiitaItemMetadata.IgnorePublish,
+		Status:        status,
+		UpdatedAt:     updatedAt,
+		Images:        images,
+
+		FrontMatter: frontMatter,
+	}
+	if item.Excerpt == "" {
+		item.Excerpt = excerpt(doc, source, s.excerptLength)
 	}
 
 	return item, nil
 }
 
-func (s *Parser) ParseAllFromQiitaItems(files *[]string) []*Item {
-	items := make([]*Item, 0, len(*files))
+// ParseResult はファイルごとの記事情報の取得結果
+type ParseResult struct {
+	// 記事情報を取得できたファイルの記事
+	Items []*Item
+	// 記事情報を取得できなかったファイルと、その原因
+	Errors []*ParseError
+}
+
+// ParseAllFromQiitaItems はファイルから記事情報を取得する
+// 記事情報を取得できなかったファイルはスキップし、その原因を結果に含める
+func (s *Parser) ParseAllFromQiitaItems(files *[]string) *ParseResult {
+	result := &ParseResult{
+		Items:  make([]*Item, 0, len(*files)),
+		Errors: make([]*ParseError, 0),
+	}
 
 	for _, file := range *files {
 		item, err := s.parseFromQiitaItem(file)
 		if err != nil {
-			log.Printf("file:[%s] parsing is skipped because: %s", file, err)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				parseErr = newParseError(file, StageRender, err)
+			}
+			result.Errors = append(result.Errors, parseErr)
 			continue
 		}
-		items = append(items, item)
+		result.Items = append(result.Items, item)
 	}
 
-	return items
+	return result
 }
 
-func parseHtml(source string) string {
-	md := goldmark.New(
-		goldmark.WithExtensions(extension.Table, extension.TaskList),
+func newMarkdown() goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithExtensions(extension.Table, extension.TaskList, NoteExtension, CodeBlockExtension, MathExtension, HeadingExtension),
 	)
+}
+
+func parseHtml(source string) (string, error) {
+	md := newMarkdown()
+	src := []byte(source)
+	return renderHtml(md, src, md.Parser().Parse(text.NewReader(src)))
+}
+
+func renderHtml(md goldmark.Markdown, source []byte, doc ast.Node) (string, error) {
 	var buf bytes.Buffer
-	if err := md.Convert([]byte(source), &buf); err != nil {
-		panic(err)
+	if err := md.Renderer().Render(&buf, source, doc); err != nil {
+		return "", fmt.Errorf("failed to render html: %w", err)
 	}
-	return buf.String()
+	return buf.String(), nil
 }
@@ -5,8 +5,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark/text"
 )
 
 func TestNewClient(t *testing.T) {
@@ -21,37 +23,174 @@ func TestParseFromQiitaItem(t *testing.T) {
 		name          string
 		file          string
 		expectedItem  *Item
+		expectedStage ParseStage
 		expectedError string
 	}{
 		{
 			name: "正常系",
 			file: "parseItem/success.md",
 			expectedItem: &Item{
-				Title:   "テスト用の記事",
-				Tags:    "Test1,Test2",
-				QiitaID: "abcdefg12345",
-				Content: "<h2>これはテスト用の記事です。</h2>\n<p>これはテスト用の記事です。</p>\n",
+				Title:     "テスト用の記事",
+				Tags:      "Test1,Test2",
+				TagNames:  []string{"Test1", "Test2"},
+				QiitaID:   "abcdefg12345",
+				Content:   "<h2 id=\"これはテスト用の記事です\">これはテスト用の記事です。</h2>\n<p>これはテスト用の記事です。</p>\n",
+				Excerpt:   "これはテスト用の記事です。",
+				UpdatedAt: time.Date(2025, 3, 23, 20, 50, 41, 0, time.FixedZone("", 9*60*60)),
+				FrontMatter: map[string]interface{}{
+					"title":                 "テスト用の記事",
+					"tags":                  []interface{}{"Test1", "Test2"},
+					"private":               false,
+					"updated_at":            "2025-03-23T20:50:41+09:00",
+					"id":                    "abcdefg12345",
+					"organization_url_name": nil,
+					"slide":                 false,
+					"ignorePublish":         false,
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name: "正常系_private",
+			file: "parseItem/private.md",
+			expectedItem: &Item{
+				Title:     "限定共有の記事",
+				Tags:      "Test1",
+				TagNames:  []string{"Test1"},
+				QiitaID:   "hijklmn67890",
+				Content:   "<h2 id=\"これは限定共有の記事です\">これは限定共有の記事です。</h2>\n",
+				Private:   true,
+				UpdatedAt: time.Date(2025, 3, 23, 20, 50, 41, 0, time.FixedZone("", 9*60*60)),
+				FrontMatter: map[string]interface{}{
+					"title":                 "限定共有の記事",
+					"tags":                  []interface{}{"Test1"},
+					"private":               true,
+					"updated_at":            "2025-03-23T20:50:41+09:00",
+					"id":                    "hijklmn67890",
+					"organization_url_name": nil,
+					"slide":                 false,
+					"ignorePublish":         false,
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name: "正常系_ignorePublishの記事はidがなくてもよい",
+			file: "parseItem/ignorePublish.md",
+			expectedItem: &Item{
+				Title:         "Qiitaに投稿しない下書き",
+				Tags:          "Test1",
+				TagNames:      []string{"Test1"},
+				IgnorePublish: true,
+			},
+			expectedError: "",
+		},
+		{
+			name: "正常系_draft",
+			file: "parseItem/draft.md",
+			expectedItem: &Item{
+				Title:     "下書きの記事",
+				Tags:      "Test1",
+				TagNames:  []string{"Test1"},
+				QiitaID:   "opqrstu12345",
+				Content:   "<h2 id=\"これは下書きの記事です\">これは下書きの記事です。</h2>\n",
+				Status:    "draft",
+				UpdatedAt: time.Date(2025, 3, 23, 20, 50, 41, 0, time.FixedZone("", 9*60*60)),
+				FrontMatter: map[string]interface{}{
+					"title":                 "下書きの記事",
+					"tags":                  []interface{}{"Test1"},
+					"private":               false,
+					"updated_at":            "2025-03-23T20:50:41+09:00",
+					"id":                    "opqrstu12345",
+					"organization_url_name": nil,
+					"slide":                 false,
+					"ignorePublish":         false,
+					"microcms":              map[string]interface{}{"status": "draft"},
+				},
 			},
 			expectedError: "",
 		},
+		{
+			name: "正常系_description",
+			file: "parseItem/withDescription.md",
+			expectedItem: &Item{
+				Title:     "概要付きの記事",
+				Tags:      "Test1",
+				TagNames:  []string{"Test1"},
+				QiitaID:   "vwxyz123456",
+				Content:   "<h2 id=\"見出し\">見出し</h2>\n<p>本文です。</p>\n",
+				Excerpt:   "記事の概要です。",
+				UpdatedAt: time.Date(2025, 3, 23, 20, 50, 41, 0, time.FixedZone("", 9*60*60)),
+				FrontMatter: map[string]interface{}{
+					"title":                 "概要付きの記事",
+					"tags":                  []interface{}{"Test1"},
+					"private":               false,
+					"updated_at":            "2025-03-23T20:50:41+09:00",
+					"id":                    "vwxyz123456",
+					"organization_url_name": nil,
+					"slide":                 false,
+					"ignorePublish":         false,
+					"description":           "記事の概要です。",
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name:          "異常系_ファイルがない",
+			file:          "parseItem/notFound.md",
+			expectedItem:  nil,
+			expectedStage: StageRead,
+			expectedError: "open ../../mocks/parseItem/notFound.md: no such file or directory",
+		},
 		{
 			name:          "異常系_invalidFrontMatter",
 			file:          "parseItem/invalidFrontMatter.md",
 			expectedItem:  nil,
-			expectedError: "invalid front matter format",
+			expectedStage: StageFrontMatter,
+			expectedError: "invalid front matter format: the first line must be ---",
 		},
 		{
 			name:          "異常系_invalidMetadata",
 			file:          "parseItem/invalidMetadata.md",
 			expectedItem:  nil,
-			expectedError: "invalid metadata format",
+			expectedStage: StageMetadata,
+			expectedError: "invalid metadata format: line 3: cannot unmarshal !!str `Test1` into []string",
+		},
+		{
+			name:          "異常系_invalidYaml",
+			file:          "parseItem/invalidYaml.md",
+			expectedItem:  nil,
+			expectedStage: StageMetadata,
+			expectedError: "invalid metadata format: line 5: mapping values are not allowed in this context",
+		},
+		{
+			name:          "異常系_invalidStatus",
+			file:          "parseItem/invalidStatus.md",
+			expectedItem:  nil,
+			expectedStage: StageMetadata,
+			expectedError: "microcms.status must be publish or draft",
+		},
+		{
+			name:          "異常系_invalidUpdatedAt",
+			file:          "parseItem/invalidUpdatedAt.md",
+			expectedItem:  nil,
+			expectedStage: StageMetadata,
+			expectedError: "invalid updated_at format",
 		},
 		{
 			name:          "異常系_withoutIdAndTilte",
 			file:          "parseItem/withoutIdAndTitle.md",
 			expectedItem:  nil,
+			expectedStage: StageMetadata,
 			expectedError: "title or id is empty",
 		},
+		{
+			name:          "異常系_ワークスペース外の画像",
+			file:          "parseItem/withOutsideImage.md",
+			expectedItem:  nil,
+			expectedStage: StageImages,
+			expectedError: "image ../../../../etc/passwd is outside the workspace",
+		},
 	}
 
 	for _, tt := range tests {
@@ -66,20 +205,56 @@ func TestParseFromQiitaItem(t *testing.T) {
 
 			// then
 			if tt.expectedError != "" {
-				assert.Error(t, err)
 				assert.Nil(t, item)
-				assert.Equal(t, tt.expectedError, err.Error())
+				var parseErr *ParseError
+				if assert.ErrorAs(t, err, &parseErr) {
+					assert.Equal(t, tt.file, parseErr.Path)
+					assert.Equal(t, tt.expectedStage, parseErr.Stage)
+					assert.EqualError(t, parseErr.Err, tt.expectedError)
+				}
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedItem.Title, item.Title)
 				assert.Equal(t, tt.expectedItem.Tags, item.Tags)
+				assert.Equal(t, tt.expectedItem.TagNames, item.TagNames)
 				assert.Equal(t, tt.expectedItem.QiitaID, item.QiitaID)
 				assert.Equal(t, tt.expectedItem.Content, item.Content)
+				assert.Equal(t, tt.expectedItem.Excerpt, item.Excerpt)
+				assert.Equal(t, tt.expectedItem.Private, item.Private)
+				assert.Equal(t, tt.expectedItem.IgnorePublish, item.IgnorePublish)
+				assert.Equal(t, tt.expectedItem.Status, item.Status)
+				assert.True(t, tt.expectedItem.UpdatedAt.Equal(item.UpdatedAt), "expected %v, got %v", tt.expectedItem.UpdatedAt, item.UpdatedAt)
+				assert.Equal(t, tt.expectedItem.FrontMatter, item.FrontMatter)
 			}
 		})
 	}
 }
 
+func TestParseFromQiitaItem_AllowMissingID(t *testing.T) {
+	t.Run("正常系_idのない記事", func(t *testing.T) {
+		parser := NewParser("../../mocks", AllowMissingID())
+
+		item, err := parser.parseFromQiitaItem("parseItem/withoutId.md")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "投稿前の記事", item.Title)
+		assert.Equal(t, "", item.QiitaID)
+		assert.Equal(t, "<h2 id=\"これは投稿前の記事です\">これは投稿前の記事です。</h2>\n", item.Content)
+	})
+
+	t.Run("異常系_タイトルのない記事", func(t *testing.T) {
+		parser := NewParser("../../mocks", AllowMissingID())
+
+		_, err := parser.parseFromQiitaItem("parseItem/withoutIdAndTitle.md")
+
+		var parseErr *ParseError
+		if assert.ErrorAs(t, err, &parseErr) {
+			assert.Equal(t, StageMetadata, parseErr.Stage)
+			assert.EqualError(t, parseErr.Err, "title or id is empty")
+		}
+	})
+}
+
 // モック用のParser構造体
 type MockParser struct {
 	Parser
@@ -96,24 +271,30 @@ func (m *MockParser) parseFromQiitaItem(file string) (*Item, error) {
 
 func TestParseAllFromQiitaItems(t *testing.T) {
 	tests := []struct {
-		names         string
-		files         []string
-		expectedItems []*Item
+		names          string
+		files          []string
+		expectedItems  []*Item
+		expectedErrors []*ParseError
 	}{
 		{
 			names: "正常系",
 			files: []string{
 				"parseItem/success.md",
 				"parseItem/invalidFrontMatter.md",
+				"parseItem/notFound.md",
 			},
 			expectedItems: []*Item{
 				{
 					Title:   "テスト用の記事",
 					Tags:    "Test1,Test2",
 					QiitaID: "abcdefg12345",
-					Content: "<h2>これはテスト用の記事です。</h2>\n<p>これはテスト用の記事です。</p>\n",
+					Content: "<h2 id=\"これはテスト用の記事です\">これはテスト用の記事です。</h2>\n<p>これはテスト用の記事です。</p>\n",
 				},
 			},
+			expectedErrors: []*ParseError{
+				{Path: "parseItem/invalidFrontMatter.md", Stage: StageFrontMatter},
+				{Path: "parseItem/notFound.md", Stage: StageRead},
+			},
 		},
 	}
 
@@ -134,12 +315,13 @@ func TestParseAllFromQiitaItems(t *testing.T) {
 					Title:   "テスト用の記事",
 					Tags:    "Test1,Test2",
 					QiitaID: "abcdefg12345",
-					Content: "<h2>これはテスト用の記事です。</h2>\n<p>これはテスト用の記事です。</p>\n",
+					Content: "<h2 id=\"これはテスト用の記事です\">これはテスト用の記事です。</h2>\n<p>これはテスト用の記事です。</p>\n",
 				}, nil
 			}
 
 			// when
-			items := mockParser.ParseAllFromQiitaItems(&tt.files)
+			result := mockParser.ParseAllFromQiitaItems(&tt.files)
+			items := result.Items
 
 			// then
 			assert.Equal(t, len(tt.expectedItems), len(items))
@@ -149,6 +331,12 @@ func TestParseAllFromQiitaItems(t *testing.T) {
 				assert.Equal(t, expected.QiitaID, items[i].QiitaID)
 				assert.Equal(t, expected.Content, items[i].Content)
 			}
+			assert.Equal(t, len(tt.expectedErrors), len(result.Errors))
+			for i, expected := range tt.expectedErrors {
+				assert.Equal(t, expected.Path, result.Errors[i].Path)
+				assert.Equal(t, expected.Stage, result.Errors[i].Stage)
+				assert.Error(t, result.Errors[i].Err)
+			}
 		})
 	}
 }
@@ -162,7 +350,27 @@ func TestParseHtml(t *testing.T) {
 		{
 			name:           "正常系",
 			targetFilePath: "../../mocks/parseHtml/success.md",
-			expected:       "<h1>タイトル1です</h1>\n<p>ここは導入文です。この記事では、Markdownの基本文法について説明します。</p>\n<h2>タイトル2です</h2>\n<p>Markdownは<strong>シンプル</strong>で、_可読性_が高く、<code>コード</code>も簡単に書けます。</p>\n<h3>タイトル3です</h3>\n<p>以下にさまざまなMarkdownの構文を紹介します。</p>\n<hr>\n<h3>見出し</h3>\n<h1>見出し1</h1>\n<h2>見出し2</h2>\n<h3>見出し3</h3>\n<h4>見出し4</h4>\n<h5>見出し5</h5>\n<h6>見出し6</h6>\n<hr>\n<h3>リスト</h3>\n<ul>\n<li>箇条書き1\n<ul>\n<li>ネスト1\n<ul>\n<li>ネスト2</li>\n</ul>\n</li>\n</ul>\n</li>\n<li>箇条書き2</li>\n</ul>\n<ol>\n<li>番号付きリスト1</li>\n<li>番号付きリスト2\n<ol>\n<li>ネストされた番号付きリスト</li>\n</ol>\n</li>\n</ol>\n<hr>\n<h3>引用</h3>\n<blockquote>\n<p>これは引用です。<br>\n引用内で改行もできます。</p>\n</blockquote>\n<hr>\n<h3>コードブロック</h3>\n<h4>インラインコード</h4>\n<p>例えば、<code>console.log(&quot;Hello World&quot;)</code>のように書きます。</p>\n<h4>ブロックコード（シンタックスハイライト付き）</h4>\n<pre><code class=\"language-javascript\">function greet(name) {\n  console.log(`Hello, ${name}!`);\n}\ngreet(&quot;Markdown&quot;);\n</code></pre>\n<hr>\n<h3>テーブル</h3>\n<table>\n<thead>\n<tr>\n<th>名前</th>\n<th>年齢</th>\n<th>職業</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>山田太郎</td>\n<td>29</td>\n<td>エンジニア</td>\n</tr>\n<tr>\n<td>田中花子</td>\n<td>34</td>\n<td>デザイナー</td>\n</tr>\n</tbody>\n</table>\n<hr>\n<h3>リンクと画像</h3>\n<p><a href=\"https://www.google.com\">Google</a></p>\n<p><img src=\"https://images.dog.ceo/breeds/pembroke/n02113023_15998.jpg\" alt=\"ダミー画像\"></p>\n<hr>\n<h3>太字・斜体・打ち消し</h3>\n<ul>\n<li><strong>太字</strong></li>\n<li><em>斜体</em></li>\n<li>~~打ち消し~~</li>\n</ul>\n<hr>\n<h3>チェックリスト</h3>\n<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> 記事構成を考える</li>\n<li><input disabled=\"\" type=\"checkbox\"> 実装する</li>\n<li><input disabled=\"\" type=\"checkbox\"> 公開する</li>\n</ul>\n<hr>\n<h3>改行の確認</h3>\n<p>この文の後には2スペースがあります。<br>\nなので改行されます。</p>\n<hr>\n<p>おわりに。この記事ではMarkdownの様々な構文を紹介しました。</p>\n",
+			expected:       "<h1 id=\"タイトル1です\">タイトル1です</h1>\n<p>ここは導入文です。この記事では、Markdownの基本文法について説明します。</p>\n<h2 id=\"タイトル2です\">タイトル2です</h2>\n<p>Markdownは<strong>シンプル</strong>で、_可読性_が高く、<code>コード</code>も簡単に書けます。</p>\n<h3 id=\"タイトル3です\">タイトル3です</h3>\n<p>以下にさまざまなMarkdownの構文を紹介します。</p>\n<hr>\n<h3 id=\"見出し\">見出し</h3>\n<h1 id=\"見出し1\">見出し1</h1>\n<h2 id=\"見出し2\">見出し2</h2>\n<h3 id=\"見出し3\">見出し3</h3>\n<h4 id=\"見出し4\">見出し4</h4>\n<h5 id=\"見出し5\">見出し5</h5>\n<h6 id=\"見出し6\">見出し6</h6>\n<hr>\n<h3 id=\"リスト\">リスト</h3>\n<ul>\n<li>箇条書き1\n<ul>\n<li>ネスト1\n<ul>\n<li>ネスト2</li>\n</ul>\n</li>\n</ul>\n</li>\n<li>箇条書き2</li>\n</ul>\n<ol>\n<li>番号付きリスト1</li>\n<li>番号付きリスト2\n<ol>\n<li>ネストされた番号付きリスト</li>\n</ol>\n</li>\n</ol>\n<hr>\n<h3 id=\"引用\">引用</h3>\n<blockquote>\n<p>これは引用です。<br>\n引用内で改行もできます。</p>\n</blockquote>\n<hr>\n<h3 id=\"コードブロック\">コードブロック</h3>\n<h4 id=\"インラインコード\">インラインコード</h4>\n<p>例えば、<code>console.log(&quot;Hello World&quot;)</code>のように書きます。</p>\n<h4 id=\"ブロックコードシンタックスハイライト付き\">ブロックコード（シンタックスハイライト付き）</h4>\n<pre><code class=\"language-javascript\">function greet(name) {\n  console.log(`Hello, ${name}!`);\n}\ngreet(&quot;Markdown&quot;);\n</code></pre>\n<hr>\n<h3 id=\"テーブル\">テーブル</h3>\n<table>\n<thead>\n<tr>\n<th>名前</th>\n<th>年齢</th>\n<th>職業</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>山田太郎</td>\n<td>29</td>\n<td>エンジニア</td>\n</tr>\n<tr>\n<td>田中花子</td>\n<td>34</td>\n<td>デザイナー</td>\n</tr>\n</tbody>\n</table>\n<hr>\n<h3 id=\"リンクと画像\">リンクと画像</h3>\n<p><a href=\"https://www.google.com\">Google</a></p>\n<p><img src=\"https://images.dog.ceo/breeds/pembroke/n02113023_15998.jpg\" alt=\"ダミー画像\"></p>\n<hr>\n<h3 id=\"太字斜体打ち消し\">太字・斜体・打ち消し</h3>\n<ul>\n<li><strong>太字</strong></li>\n<li><em>斜体</em></li>\n<li>~~打ち消し~~</li>\n</ul>\n<hr>\n<h3 id=\"チェックリスト\">チェックリスト</h3>\n<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> 記事構成を考える</li>\n<li><input disabled=\"\" type=\"checkbox\"> 実装する</li>\n<li><input disabled=\"\" type=\"checkbox\"> 公開する</li>\n</ul>\n<hr>\n<h3 id=\"改行の確認\">改行の確認</h3>\n<p>この文の後には2スペースがあります。<br>\nなので改行されます。</p>\n<hr>\n<p>おわりに。この記事ではMarkdownの様々な構文を紹介しました。</p>\n",
+		},
+		{
+			name:           "正常系_note",
+			targetFilePath: "../../mocks/parseHtml/note.md",
+			expected:       "<div class=\"note info\">\n<p>種類を省略するとinfoになります。</p>\n</div>\n<div class=\"note warn\">\n<p><strong>注意</strong>してください。</p>\n<ul>\n<li>リストも書けます</li>\n</ul>\n</div>\n<div class=\"note alert\">\n<p>危険です。</p>\n</div>\n<div class=\"note info\">\n<p>未知の種類はinfoになります。</p>\n</div>\n<div class=\"note warn\">\n<pre><code class=\"language-sh\">echo\n:::\n</code></pre>\n<p>after</p>\n</div>\n<div class=\"note warn\">\n<ul>\n<li>item\n<pre><code class=\"language-sh\">:::\n</code></pre>\n</li>\n</ul>\n</div>\n<p>:::notes\nこれはnoteではありません。\n:::</p>\n",
+		},
+		{
+			name:           "正常系_codeBlock",
+			targetFilePath: "../../mocks/parseHtml/codeBlock.md",
+			expected:       "<div class=\"code-frame\" data-filename=\"main.go\">\n<div class=\"code-filename\">main.go</div>\n<pre><code class=\"language-go\">fmt.Println(&quot;&lt;Hello&gt;&quot;)\n</code></pre>\n</div>\n<div class=\"code-frame\" data-filename=\"memo.txt\">\n<div class=\"code-filename\">memo.txt</div>\n<pre><code>ファイル名のみ\n</code></pre>\n</div>\n<pre><code class=\"language-ruby\">puts 1\n</code></pre>\n<pre><code>言語なし\n</code></pre>\n",
+		},
+		{
+			name:           "正常系_math",
+			targetFilePath: "../../mocks/parseHtml/math.md",
+			expected:       "<p>インライン数式 <span class=\"math inline\">a_1 + b_1 &lt; c</span> と <em>強調</em> が混在します。</p>\n<p>$5 と $10 は数式ではありません。</p>\n<p>文中の <span class=\"math inline\">y = x^2</span> もインライン数式です。閉じていない $$ はそのままです。</p>\n<p><span class=\"math inline\">a</span> は行頭のインライン数式です。</p>\n<p>次の段落は数式に含めません。</p>\n<div class=\"math display\">\\sum_{i=1}^{n} x_i\n</div>\n<div class=\"math display\">E = mc^2</div>\n<div class=\"math display\">y = a_1 x + b_1\n</div>\n",
+		},
+		{
+			name:           "正常系_heading",
+			targetFilePath: "../../mocks/parseHtml/heading.md",
+			expected:       "<h2 id=\"go-の基本\">Go の基本</h2>\n<h3 id=\"fmt-パッケージ\"><code>fmt</code> パッケージ</h3>\n<h3 id=\"概要\">概要</h3>\n<h2 id=\"応用-go\">応用 Go</h2>\n<h3 id=\"概要-1\">概要</h3>\n<div class=\"note info\">\n<h2 id=\"noteの中の見出し\">noteの中の見出し</h2>\n</div>\n",
 		},
 	}
 
@@ -177,8 +385,155 @@ func TestParseHtml(t *testing.T) {
 			parts := strings.SplitN(string(content), "---\n", 3)
 
 			// when
-			result := parseHtml(parts[2])
+			result, err := parseHtml(parts[2])
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestCollectImages(t *testing.T) {
+	// given
+	mockWorkspace := "../../mocks"
+	parser := NewParser(mockWorkspace)
+
+	// when
+	item, err := parser.parseFromQiitaItem("parseItem/withImages.md")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []Image{
+		{Src: "images/sample.png", Path: "../../mocks/parseItem/images/sample.png"},
+		{Src: "/images/root.png", Path: "../../mocks/images/root.png"},
+		{Src: "https://qiita-image-store.s3.amazonaws.com/0/12345/sample.png", Path: ""},
+	}, item.Images)
+}
+
+func TestCollectHeadings(t *testing.T) {
+	// given
+	parser := NewParser("../../mocks")
+
+	// when
+	item, err := parser.parseFromQiitaItem("parseItem/withHeadings.md")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []*Heading{
+		{ID: "はじめに", Text: "はじめに", Level: 1, Children: []*Heading{
+			{ID: "go-の-基本", Text: "Go の 基本", Level: 2, Children: []*Heading{
+				{ID: "変数", Text: "変数", Level: 3},
+			}},
+			{ID: "まとめ", Text: "まとめ", Level: 2, Children: []*Heading{
+				{ID: "深い見出し", Text: "深い見出し", Level: 4},
+			}},
+		}},
+	}, item.Headings)
+}
+
+func TestRenderTOC(t *testing.T) {
+	tests := []struct {
+		name     string
+		headings []*Heading
+		expected string
+	}{
+		{
+			name: "正常系",
+			headings: []*Heading{
+				{ID: "go-の基本", Text: "Go の基本", Level: 2, Children: []*Heading{
+					{ID: "a-b", Text: "a < b", Level: 3},
+				}},
+				{ID: "まとめ", Text: "まとめ", Level: 2},
+			},
+			expected: "<nav class=\"toc\">\n<ul>\n<li><a href=\"#go-の基本\">Go の基本</a>\n<ul>\n<li><a href=\"#a-b\">a &lt; b</a></li>\n</ul>\n</li>\n<li><a href=\"#まとめ\">まとめ</a></li>\n</ul>\n</nav>\n",
+		},
+		{
+			name:     "正常系_見出しなし",
+			headings: []*Heading{},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RenderTOC(tt.headings))
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		length   int
+		expected string
+	}{
+		{
+			name:     "正常系",
+			source:   "## 見出し\n\n**Go**の[記事](https://example.com)です。\n\n- `fmt`を使います\n",
+			length:   120,
+			expected: "Goの記事です。 fmtを使います",
+		},
+		{
+			name:     "正常系_日本語を文字数で切り詰める",
+			source:   "あいうえおかきくけこ\n",
+			length:   5,
+			expected: "あいうえお…",
+		},
+		{
+			name:     "正常系_コード・数式・画像・HTMLを除く",
+			source:   "![画像](a.png)<span>HTML</span>本文\n\n```go\nfmt.Println()\n```\n\n$$\nx\n$$\n\n| a |\n|---|\n| b |\n\n<div>ブロック</div>\n",
+			length:   120,
+			expected: "HTML本文",
+		},
+		{
+			name:     "正常系_本文なし",
+			source:   "## 見出しだけ\n",
+			length:   120,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := []byte(tt.source)
+			doc := newMarkdown().Parser().Parse(text.NewReader(source))
+
+			assert.Equal(t, tt.expected, excerpt(doc, source, tt.length))
+		})
+	}
+}
+
+func TestReplaceImageSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		urls     map[string]string
+		expected string
+	}{
+		{
+			name:     "正常系",
+			content:  "<p><img src=\"images/sample.png\" alt=\"a\"></p>\n<p><img src=\"https://example.com/b.png\" alt=\"b\"></p>\n",
+			urls:     map[string]string{"images/sample.png": "https://images.microcms-assets.io/assets/x/y/sample.png"},
+			expected: "<p><img src=\"https://images.microcms-assets.io/assets/x/y/sample.png\" alt=\"a\"></p>\n<p><img src=\"https://example.com/b.png\" alt=\"b\"></p>\n",
+		},
+		{
+			name:     "正常系_エスケープされるパス",
+			content:  "<p><img src=\"images/%E7%94%BB%E5%83%8F.png\" alt=\"a\"></p>\n",
+			urls:     map[string]string{"images/画像.png": "https://images.microcms-assets.io/assets/x/y/image.png"},
+			expected: "<p><img src=\"https://images.microcms-assets.io/assets/x/y/image.png\" alt=\"a\"></p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			item := &Item{Content: tt.content}
+
+			// when
+			item.ReplaceImageSources(tt.urls)
+
+			// then
+			assert.Equal(t, tt.expected, item.Content)
+		})
+	}
+}